@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// Makes a command's client call once its flags are parsed.
+type call func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error)
+
+// A subcommand, calling one OpenSecretsClient method.
+type command struct {
+	name        string
+	description string
+	// Registers the command's flags, which mirror its request struct, and returns the call to make with them.
+	setup func(flags *flag.FlagSet) call
+}
+
+// Every command, in the order they're listed in usage.
+var commands = []command{
+	{"legislators", "Legislators for a state, or one legislator by CID (GetLegislators)", legislatorsCommand},
+	{"pfd", "A member's personal financial disclosure (GetMemberPFDProfile)", pfdCommand},
+	{"summary", "A candidate's fundraising summary (GetCandidateSummary)", summaryCommand},
+	{"contributors", "A candidate's top contributors (GetCandidateContributors)", contributorsCommand},
+	{"industries", "A candidate's top industries (GetCandidateIndustries)", industriesCommand},
+	{"industry", "What a candidate received from one industry (GetCandidateIndustryDetails)", industryCommand},
+	{"sectors", "A candidate's receipts by sector (GetCandidateTopSectorDetails)", sectorsCommand},
+	{"committee", "Fundraising from an industry by a committee's members (GetCommitteeFundraisingDetails)", committeeCommand},
+	{"orgs", "Organizations matching a name (SearchForOrganization)", orgsCommand},
+	{"org", "An organization's fundraising summary (GetOrganizationSummary)", orgCommand},
+	{"indexp", "The latest 50 independent expenditures (GetLatestIndependentExpenditures)", indexpCommand},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, command := range commands {
+		if command.name == name {
+			return command, true
+		}
+	}
+	return command{}, false
+}
+
+func legislatorsCommand(flags *flag.FlagSet) call {
+	var request models.LegislatorsRequest
+	flags.StringVar(&request.Id, "id", "", "Required. Two-character state code, or CRP candidate ID")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		legislators, err := openSecretsClient.GetLegislators(ctx, request)
+		r := result{value: legislators, header: []string{"cid", "name", "party", "office", "first_elected", "bioguide_id", "fec_id", "votesmart_id", "phone", "website"}}
+		for _, legislator := range legislators {
+			r.rows = append(r.rows, []string{legislator.Cid, legislator.FirstLast, legislator.Party, legislator.Office,
+				integer(legislator.FirstElected), legislator.BioguideId, legislator.FECCandId, legislator.VoteSmartId, legislator.Phone, legislator.Website})
+		}
+		return r, err
+	}
+}
+
+func pfdCommand(flags *flag.FlagSet) call {
+	var request models.MemberPFDRequest
+	flags.StringVar(&request.Cid, "cid", "", "Required. CRP candidate ID")
+	flags.IntVar(&request.Year, "year", 0, "Optional. Disclosure year (2013 to 2016)")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		profile, err := openSecretsClient.GetMemberPFDProfile(ctx, request)
+		return singleResult(profile, []field{
+			{"member_id", profile.MemberId},
+			{"name", profile.Name},
+			{"data_year", integer(profile.DataYear)},
+			{"net_low", integer(profile.NetLow)},
+			{"net_high", integer(profile.NetHigh)},
+			{"asset_count", integer(profile.AssetCount)},
+			{"asset_low", integer(profile.AssetLow)},
+			{"asset_high", integer(profile.AssetHigh)},
+			{"transaction_count", integer(profile.TransactionCount)},
+			{"transaction_low", integer(profile.TransactionLow)},
+			{"transaction_high", integer(profile.TransactionHigh)},
+			{"positions_held_count", integer(profile.PositionHeldCount)},
+			{"updated", profile.UpdateTimestamp},
+		}), err
+	}
+}
+
+func summaryCommand(flags *flag.FlagSet) call {
+	var request models.CandidateSummaryRequest
+	flags.StringVar(&request.Cid, "cid", "", "Required. CRP candidate ID")
+	flags.IntVar(&request.Cycle, "cycle", 0, "Optional. Election cycle; defaults to the most recent")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		summary, err := openSecretsClient.GetCandidateSummary(ctx, request)
+		return singleResult(summary, []field{
+			{"cid", summary.Cid},
+			{"name", summary.CandidateName},
+			{"cycle", integer(summary.Cycle)},
+			{"state", summary.State},
+			{"party", summary.Party},
+			{"chamber", summary.Chamber},
+			{"first_elected", integer(summary.FirstElected)},
+			{"next_election", integer(summary.NextElection)},
+			{"total", money(summary.Total)},
+			{"spent", money(summary.Spent)},
+			{"cash_on_hand", money(summary.CashOnHand)},
+			{"debt", money(summary.Debt)},
+			{"last_updated", summary.LastUpdated},
+		}), err
+	}
+}
+
+func contributorsCommand(flags *flag.FlagSet) call {
+	var request models.CandidateContributorsRequest
+	flags.StringVar(&request.Cid, "cid", "", "Required. CRP candidate ID")
+	flags.IntVar(&request.Cycle, "cycle", 0, "Optional. Election cycle; defaults to the most recent")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		summary, err := openSecretsClient.GetCandidateContributors(ctx, request)
+		r := result{value: summary, header: []string{"organization", "total", "pacs", "individuals"}}
+		for _, contributor := range summary.Contributors {
+			r.rows = append(r.rows, []string{contributor.OrganizationName, money(contributor.Total), money(contributor.Pacs), money(contributor.Individuals)})
+		}
+		return r, err
+	}
+}
+
+func industriesCommand(flags *flag.FlagSet) call {
+	var request models.CandidateIndustriesRequest
+	flags.StringVar(&request.Cid, "cid", "", "Required. CRP candidate ID")
+	flags.IntVar(&request.Cycle, "cycle", 0, "Optional. Election cycle; defaults to the most recent")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		summary, err := openSecretsClient.GetCandidateIndustries(ctx, request)
+		r := result{value: summary, header: []string{"code", "industry", "total", "pacs", "individuals"}}
+		for _, industry := range summary.Industries {
+			r.rows = append(r.rows, []string{industry.IndustryCode, industry.IndustryName, money(industry.Total), money(industry.Pacs), money(industry.Individuals)})
+		}
+		return r, err
+	}
+}
+
+func industryCommand(flags *flag.FlagSet) call {
+	var request models.CandidateIndustryDetailsRequest
+	flags.StringVar(&request.Cid, "cid", "", "Required. CRP candidate ID")
+	flags.StringVar(&request.Ind, "ind", "", "Required. Three-character industry code")
+	flags.IntVar(&request.Cycle, "cycle", 0, "Optional. Election cycle; defaults to the most recent")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		details, err := openSecretsClient.GetCandidateIndustryDetails(ctx, request)
+		return singleResult(details, []field{
+			{"cid", details.Cid},
+			{"name", details.CandidateName},
+			{"cycle", integer(details.Cycle)},
+			{"industry", details.Industry},
+			{"chamber", details.Chamber},
+			{"party", details.Party},
+			{"state", details.State},
+			{"total", money(details.Total)},
+			{"pacs", money(details.Pacs)},
+			{"individuals", money(details.Individuals)},
+			{"rank", integer(details.Rank)},
+			{"last_updated", details.LastUpdated},
+		}), err
+	}
+}
+
+func sectorsCommand(flags *flag.FlagSet) call {
+	var request models.CandidateTopSectorsRequest
+	flags.StringVar(&request.Cid, "cid", "", "Required. CRP candidate ID")
+	flags.IntVar(&request.Cycle, "cycle", 0, "Optional. Election cycle; defaults to the most recent")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		details, err := openSecretsClient.GetCandidateTopSectorDetails(ctx, request)
+		r := result{value: details, header: []string{"id", "sector", "total", "pacs", "individuals"}}
+		for _, sector := range details.Sectors {
+			r.rows = append(r.rows, []string{sector.Id, sector.Name, money(sector.Total), money(sector.Pacs), money(sector.Individuals)})
+		}
+		return r, err
+	}
+}
+
+func committeeCommand(flags *flag.FlagSet) call {
+	var request models.FundraisingByCongressionalCommitteeRequest
+	flags.StringVar(&request.Committee, "committee", "", "Required. Committee ID in CQ format (e.g. HARM)")
+	flags.StringVar(&request.Industry, "industry", "", "Required. Industry code")
+	flags.IntVar(&request.CongressNumber, "congress", 0, "Optional. Congress number; defaults to the most recent")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		details, err := openSecretsClient.GetCommitteeFundraisingDetails(ctx, request)
+		r := result{value: details, header: []string{"cid", "member", "party", "state", "total", "pacs", "individuals"}}
+		for _, member := range details.Members {
+			r.rows = append(r.rows, []string{member.Cid, member.Name, member.Party, member.State, money(member.Total), money(member.Pacs), money(member.Individuals)})
+		}
+		return r, err
+	}
+}
+
+func orgsCommand(flags *flag.FlagSet) call {
+	var request models.OrganizationSearch
+	flags.StringVar(&request.Name, "name", "", "Required. Name or partial name of the organization")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		organizations, err := openSecretsClient.SearchForOrganization(ctx, request)
+		r := result{value: organizations, header: []string{"id", "name"}}
+		for _, organization := range organizations {
+			r.rows = append(r.rows, []string{organization.Id, organization.Name})
+		}
+		return r, err
+	}
+}
+
+func orgCommand(flags *flag.FlagSet) call {
+	var request models.OrganizationSummaryRequest
+	flags.StringVar(&request.Id, "id", "", "Required. CRP organization ID (see the orgs command)")
+
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		summary, err := openSecretsClient.GetOrganizationSummary(ctx, request)
+		return singleResult(summary, []field{
+			{"id", summary.Id},
+			{"name", summary.Name},
+			{"cycle", summary.Cycle},
+			{"total", money(summary.TotalContributions)},
+			{"pacs", money(summary.PacContributions)},
+			{"individuals", money(summary.IndividualContributions)},
+			{"soft", money(summary.Soft)},
+			{"from_527s", money(summary.TotalFrom527Organizations)},
+			{"to_democrats", money(summary.TotalToDemocrats)},
+			{"to_republicans", money(summary.TotalToRepublicans)},
+			{"lobbying", money(summary.TotalSpentLobyying)},
+			{"outside_spending", money(summary.TotalSpentOnIndependentExpenditures)},
+			{"members_invested", integer(summary.MembersInvested)},
+			{"gave_to_pacs", money(summary.TotalGaveToPacs)},
+			{"gave_to_parties", money(summary.TotalGaveToPartyCommittees)},
+			{"gave_to_527s", money(summary.TotalGaveTo527Organizations)},
+			{"gave_to_candidates", money(summary.TotalGaveToCandidates)},
+		}), err
+	}
+}
+
+func indexpCommand(flags *flag.FlagSet) call {
+	return func(ctx context.Context, openSecretsClient client.OpenSecretsClient) (result, error) {
+		expenditures, err := openSecretsClient.GetLatestIndependentExpenditures(ctx)
+		r := result{value: expenditures, header: []string{"date", "committee_id", "committee", "support_oppose", "candidate", "district", "party", "amount", "payee"}}
+		for _, expenditure := range expenditures {
+			r.rows = append(r.rows, []string{expenditure.Date, expenditure.CommitteeId, expenditure.CommitteeName, expenditure.SupportOrOppose,
+				expenditure.CandidateName, expenditure.District, expenditure.Party, money(expenditure.Amount), expenditure.Payee})
+		}
+		return r, err
+	}
+}
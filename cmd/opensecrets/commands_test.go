@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+const legislatorsResponse string = `{"response":{"legislator":[{"@attributes":{"cid":"N00033085","firstlast":"Ted Cruz","party":"R","office":"TXS2","first_elected":"2012","exit_code":"0","bioguide_id":"C001098"}}]}}`
+
+// The mock response served for each API method.
+var mockResponses = map[string]string{
+	"memPFDProfile":     "mockPFDResponse.json",
+	"candSummary":       "mockCandidateSummaryResponse.json",
+	"candContrib":       "mockCandidateContributorsResponse.json",
+	"candIndustry":      "mockCandidateIndustriesResponse.json",
+	"candIndByInd":      "mockCandidateIndustryDetailsResponse.json",
+	"candSector":        "mockCandidateTopSectorsResponse.json",
+	"congCmteIndus":     "mockFundraisingByCommitteeResponse.json",
+	"getOrgs":           "mockOrganizationSearchResponse.json",
+	"orgSummary":        "mockOrganizationSummaryResponse.json",
+	"independentExpend": "mockIndependentExpendituresResponse.json",
+}
+
+// Serves mock API responses, recording the query of each request.
+func newMockAPI(t *testing.T) (*httptest.Server, *[]url.Values) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		method := req.URL.Query().Get("method")
+		if method == "getLegislators" {
+			w.Write([]byte(legislatorsResponse))
+			return
+		}
+		body, err := os.ReadFile("../../internal/mocks/" + mockResponses[method])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &queries
+}
+
+func TestCommands(t *testing.T) {
+	cases := []struct {
+		args   []string
+		method string
+		query  map[string]string
+		output string // Expected somewhere in the table output
+	}{
+		{[]string{"legislators", "--id", "TX"}, "getLegislators", map[string]string{"id": "TX"}, "Ted Cruz"},
+		{[]string{"pfd", "--cid", "N00007360", "--year", "2016"}, "memPFDProfile", map[string]string{"cid": "N00007360", "year": "2016"}, "Pelosi, Nancy"},
+		{[]string{"summary", "--cid", "N00007360", "--cycle", "2020"}, "candSummary", map[string]string{"cid": "N00007360", "cycle": "2020"}, "Pelosi, Nancy"},
+		{[]string{"contributors", "--cid", "N00007360"}, "candContrib", map[string]string{"cid": "N00007360"}, "University of California"},
+		{[]string{"industries", "--cid", "N00005681"}, "candIndustry", map[string]string{"cid": "N00005681"}, "Leadership PACs"},
+		{[]string{"industry", "--cid", "N00007360", "--ind", "K02"}, "candIndByInd", map[string]string{"cid": "N00007360", "ind": "K02"}, "Pelosi, Nancy"},
+		{[]string{"sectors", "--cid", "N00007360"}, "candSector", map[string]string{"cid": "N00007360"}, "Agribusiness"},
+		{[]string{"committee", "--committee", "HARM", "--industry", "F10", "--congress", "116"}, "congCmteIndus", map[string]string{"cmte": "HARM", "indus": "F10", "congno": "116"}, "Stefanik, Elise"},
+		{[]string{"orgs", "--name", "Goldman"}, "getOrgs", map[string]string{"org": "Goldman"}, "Goldman Environmental Prize"},
+		{[]string{"org", "--id", "D000000125"}, "orgSummary", map[string]string{"id": "D000000125"}, "General Electric"},
+		{[]string{"indexp"}, "independentExpend", map[string]string{}, "Targeted Victory LLC"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.args[0], func(t *testing.T) {
+			server, queries := newMockAPI(t)
+			var stdout, stderr bytes.Buffer
+			args := append(c.args, "--api-key", "hunter2", "--base-url", server.URL+"/api/")
+
+			code := run(context.Background(), args, func(string) string { return "" }, &stdout, &stderr)
+			test.AssertIntMatches(code, exitSuccess, t)
+			test.AssertSliceLength(len(*queries), 1, t)
+
+			query := (*queries)[0]
+			test.AssertStringMatches(query.Get("method"), c.method, t)
+			for name, value := range c.query {
+				test.AssertStringMatches(query.Get(name), value, t)
+			}
+			if !strings.Contains(stdout.String(), c.output) {
+				t.Errorf("Wanted output containing %q but got\n%s", c.output, stdout.String())
+			}
+		})
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	t.Run("Has a command for every client method", func(t *testing.T) {
+		test.AssertSliceLength(len(commands), 11, t)
+		if _, found := findCommand("indexp"); !found {
+			t.Error("Wanted to find indexp")
+		}
+		if _, found := findCommand("nope"); found {
+			t.Error("Wanted not to find nope")
+		}
+	})
+}
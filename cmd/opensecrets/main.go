@@ -0,0 +1,139 @@
+/*
+Command opensecrets calls the OpenSecrets API from the command line, with a subcommand for each OpenSecretsClient
+method:
+
+	opensecrets <command> [flags]
+
+	opensecrets legislators --id TX
+	opensecrets summary --cid N00007360 --cycle 2022 --format json
+	opensecrets contributors --cid N00007360 --format csv > contributors.csv
+
+Each command's flags mirror the fields of its request struct in the models package. Every command also accepts:
+
+	--api-key   OpenSecrets API key; defaults to the OPENSECRETS_API_KEY environment variable
+	--base-url  Base URL to send requests to; defaults to OPENSECRETS_BASE_URL, then the OpenSecrets API
+	--format    table (default), json or csv
+	--timeout   How long to wait for the API (default 10s)
+
+Run opensecrets <command> --help for a command's flags.
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/validation"
+)
+
+const (
+	apiKeyEnvironmentVariable  string = "OPENSECRETS_API_KEY"
+	baseURLEnvironmentVariable string = "OPENSECRETS_BASE_URL"
+)
+
+const defaultTimeout time.Duration = 10 * time.Second
+
+// Exit codes
+const (
+	exitSuccess int = 0
+	exitFailure int = 1 // The API call failed
+	exitUsage   int = 2 // The command line was invalid, including flags that fail request validation
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Getenv, os.Stdout, os.Stderr))
+}
+
+// Runs the command line in args (without the program name) and returns the exit code.
+func run(ctx context.Context, args []string, getenv func(string) string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printUsage(stdout)
+		return exitSuccess
+	}
+
+	command, found := findCommand(args[0])
+	if !found {
+		fmt.Fprintf(stderr, "opensecrets: unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("opensecrets "+command.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apiKey := flags.String("api-key", getenv(apiKeyEnvironmentVariable), "OpenSecrets API key (or set "+apiKeyEnvironmentVariable+")")
+	baseURL := flags.String("base-url", getenv(baseURLEnvironmentVariable), "Base URL to send requests to (or set "+baseURLEnvironmentVariable+")")
+	formatName := flags.String("format", string(tableFormat), "Output format: table, json or csv")
+	timeout := flags.Duration("timeout", defaultTimeout, "How long to wait for the API")
+	call := command.setup(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: opensecrets %s [flags]\n\n%s\n\nFlags:\n", command.name, command.description)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "opensecrets: unexpected arguments %q\n", flags.Args())
+		return exitUsage
+	}
+	outputFormat, err := parseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(stderr, "opensecrets: %v\n", err)
+		return exitUsage
+	}
+	if *apiKey == "" {
+		fmt.Fprintf(stderr, "opensecrets: no API key; pass --api-key or set %s\n", apiKeyEnvironmentVariable)
+		return exitUsage
+	}
+
+	var options []client.Option
+	if *baseURL != "" {
+		options = append(options, client.WithBaseURL(*baseURL))
+	}
+	openSecretsClient := client.NewOpenSecretsClientWithHttpClient(*apiKey, &http.Client{Timeout: *timeout}, options...)
+
+	result, err := call(ctx, openSecretsClient)
+	var validationErrors validation.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fmt.Fprintf(stderr, "opensecrets %s: %v; run opensecrets %s --help for its flags\n", command.name, err, command.name)
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "opensecrets %s: %v\n", command.name, err)
+		return exitFailure
+	}
+	if err := write(stdout, outputFormat, result); err != nil {
+		fmt.Fprintf(stderr, "opensecrets %s: writing output: %v\n", command.name, err)
+		return exitFailure
+	}
+	return exitSuccess
+}
+
+func printUsage(writer io.Writer) {
+	fmt.Fprintln(writer, "Usage: opensecrets <command> [flags]")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "Commands:")
+	for _, command := range commands {
+		fmt.Fprintf(writer, "  %-13s %s\n", command.name, command.description)
+	}
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "Run opensecrets <command> --help for a command's flags.")
+}
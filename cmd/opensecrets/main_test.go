@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+)
+
+func runWithEnvironment(args []string, environment map[string]string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, func(name string) string { return environment[name] }, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Reads the API key and base URL from the environment", func(t *testing.T) {
+		server, queries := newMockAPI(t)
+		code, _, stderr := runWithEnvironment([]string{"legislators", "--id", "TX"}, map[string]string{
+			apiKeyEnvironmentVariable:  "hunter2",
+			baseURLEnvironmentVariable: server.URL + "/api/",
+		})
+		test.AssertIntMatches(code, exitSuccess, t)
+		test.AssertStringMatches(stderr, "", t)
+		test.AssertStringMatches((*queries)[0].Get("apikey"), "hunter2", t)
+	})
+	t.Run("Prefers flags to the environment", func(t *testing.T) {
+		server, queries := newMockAPI(t)
+		code, _, _ := runWithEnvironment([]string{"legislators", "--id", "TX", "--api-key", "flagkey", "--base-url", server.URL + "/api/"},
+			map[string]string{apiKeyEnvironmentVariable: "hunter2", baseURLEnvironmentVariable: "http://127.0.0.1:1/"})
+		test.AssertIntMatches(code, exitSuccess, t)
+		test.AssertStringMatches((*queries)[0].Get("apikey"), "flagkey", t)
+	})
+	t.Run("Writes the requested format", func(t *testing.T) {
+		server, _ := newMockAPI(t)
+		code, stdout, _ := runWithEnvironment([]string{"orgs", "--name", "Goldman", "--format", "csv", "--api-key", "hunter2", "--base-url", server.URL + "/api/"}, nil)
+		test.AssertIntMatches(code, exitSuccess, t)
+		if !strings.HasPrefix(stdout, "id,name\nD000070392,Goldman Environmental Prize\n") {
+			t.Errorf("Wanted CSV output but got\n%s", stdout)
+		}
+	})
+	t.Run("Prints usage without a command", func(t *testing.T) {
+		code, _, stderr := runWithEnvironment(nil, nil)
+		test.AssertIntMatches(code, exitUsage, t)
+		if !strings.Contains(stderr, "contributors") {
+			t.Errorf("Wanted usage listing commands but got\n%s", stderr)
+		}
+	})
+	t.Run("Prints usage to stdout when asked for help", func(t *testing.T) {
+		code, stdout, _ := runWithEnvironment([]string{"help"}, nil)
+		test.AssertIntMatches(code, exitSuccess, t)
+		if !strings.Contains(stdout, "indexp") {
+			t.Errorf("Wanted usage listing commands but got\n%s", stdout)
+		}
+	})
+	t.Run("Rejects unknown commands, flags, formats and arguments", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"nope"},
+			{"summary", "--nope"},
+			{"summary", "--cid", "N00007360", "--format", "xml", "--api-key", "hunter2"},
+			{"summary", "extra", "--api-key", "hunter2"},
+		} {
+			code, _, _ := runWithEnvironment(args, nil)
+			if code != exitUsage {
+				t.Errorf("Wanted exit code %d for %q but got %d", exitUsage, args, code)
+			}
+		}
+	})
+	t.Run("Requires an API key", func(t *testing.T) {
+		code, _, stderr := runWithEnvironment([]string{"summary", "--cid", "N00007360"}, nil)
+		test.AssertIntMatches(code, exitUsage, t)
+		if !strings.Contains(stderr, apiKeyEnvironmentVariable) {
+			t.Errorf("Wanted the error to mention %s but got %q", apiKeyEnvironmentVariable, stderr)
+		}
+	})
+	t.Run("Treats requests failing validation as usage errors", func(t *testing.T) {
+		code, _, stderr := runWithEnvironment([]string{"summary", "--api-key", "hunter2"}, nil)
+		test.AssertIntMatches(code, exitUsage, t)
+		if !strings.Contains(stderr, "Cid") {
+			t.Errorf("Wanted the error to mention Cid but got %q", stderr)
+		}
+	})
+	t.Run("Exits with a failure when the API call fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		code, stdout, stderr := runWithEnvironment([]string{"indexp", "--api-key", "hunter2", "--base-url", server.URL + "/api/"}, nil)
+		test.AssertIntMatches(code, exitFailure, t)
+		test.AssertStringMatches(stdout, "", t)
+		if !strings.Contains(stderr, "503") {
+			t.Errorf("Wanted the error to mention the status code but got %q", stderr)
+		}
+		if strings.Contains(stderr, "hunter2") {
+			t.Errorf("Wanted the API key redacted from the error but got %q", stderr)
+		}
+	})
+	t.Run("Doesn't print the API key when the API can't be reached", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		code, _, stderr := runWithEnvironment([]string{"legislators", "--id", "TX", "--api-key", "hunter2", "--base-url", server.URL + "/api/"}, nil)
+		test.AssertIntMatches(code, exitFailure, t)
+		if !strings.Contains(stderr, "apikey="+client.RedactedAPIKey) {
+			t.Errorf("Wanted the error to show the redacted request URL but got %q", stderr)
+		}
+		if strings.Contains(stderr, "hunter2") {
+			t.Errorf("Wanted the API key redacted from the error but got %q", stderr)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// How results are written to standard output.
+type format string
+
+const (
+	tableFormat format = "table" // Aligned columns, or one field per line for single records
+	jsonFormat  format = "json"  // The client method's result, indented
+	csvFormat   format = "csv"   // A header row, then one row per record
+)
+
+var formats = []format{tableFormat, jsonFormat, csvFormat}
+
+func parseFormat(value string) (format, error) {
+	for _, f := range formats {
+		if string(f) == strings.ToLower(value) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q; use table, json or csv", value)
+}
+
+/*
+What a command returns: the client method's result as is for JSON, and the same data flattened into rows for tables
+and CSV. Nested lists a flattened result has no room for (like a PFD profile's assets) are only in the JSON.
+*/
+type result struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+	single bool // Whether there's exactly one record, which tables show one field per line
+}
+
+// A named value in a single record.
+type field struct {
+	name  string
+	value string
+}
+
+// Builds a result for a single record from its fields, in order.
+func singleResult(value interface{}, fields []field) result {
+	toReturn := result{value: value, single: true, rows: [][]string{{}}}
+	for _, field := range fields {
+		toReturn.header = append(toReturn.header, field.name)
+		toReturn.rows[0] = append(toReturn.rows[0], field.value)
+	}
+	return toReturn
+}
+
+func write(writer io.Writer, f format, r result) error {
+	switch f {
+	case jsonFormat:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r.value)
+	case csvFormat:
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Write(r.header)
+		csvWriter.WriteAll(r.rows)
+		return csvWriter.Error()
+	default:
+		return writeTable(writer, r)
+	}
+}
+
+func writeTable(writer io.Writer, r result) error {
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if r.single {
+		for i, field := range r.header {
+			fmt.Fprintf(tabWriter, "%s\t%s\n", field, r.rows[0][i])
+		}
+		return tabWriter.Flush()
+	}
+
+	if len(r.rows) == 0 {
+		fmt.Fprintln(writer, "No results")
+		return nil
+	}
+	fmt.Fprintln(tabWriter, strings.ToUpper(strings.Join(r.header, "\t")))
+	for _, row := range r.rows {
+		fmt.Fprintln(tabWriter, strings.Join(row, "\t"))
+	}
+	return tabWriter.Flush()
+}
+
+// Formats a dollar amount with two decimal places.
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func integer(value int) string {
+	return strconv.Itoa(value)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+var listResult = result{
+	value:  []string{"a", "b"},
+	header: []string{"id", "name"},
+	rows:   [][]string{{"D000000125", "General Electric"}, {"D000070392", "Goldman, Sachs & Co"}},
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Run("Accepts every format, ignoring case", func(t *testing.T) {
+		f, err := parseFormat("JSON")
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(string(f), string(jsonFormat), t)
+	})
+	t.Run("Rejects unknown formats", func(t *testing.T) {
+		_, err := parseFormat("xml")
+		test.AssertErrorExists(err, t)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("Writes a table with a header", func(t *testing.T) {
+		var buffer bytes.Buffer
+		test.AssertNoError(write(&buffer, tableFormat, listResult), t)
+		expected := "ID          NAME\nD000000125  General Electric\nD000070392  Goldman, Sachs & Co\n"
+		test.AssertStringMatches(buffer.String(), expected, t)
+	})
+	t.Run("Writes a single record one field per line", func(t *testing.T) {
+		var buffer bytes.Buffer
+		r := singleResult(nil, []field{{"cid", "N00007360"}, {"cash_on_hand", "100.00"}})
+		test.AssertNoError(write(&buffer, tableFormat, r), t)
+		test.AssertStringMatches(buffer.String(), "cid           N00007360\ncash_on_hand  100.00\n", t)
+	})
+	t.Run("Says when there are no results", func(t *testing.T) {
+		var buffer bytes.Buffer
+		test.AssertNoError(write(&buffer, tableFormat, result{header: []string{"id"}}), t)
+		test.AssertStringMatches(buffer.String(), "No results\n", t)
+	})
+	t.Run("Writes CSV, quoting as needed", func(t *testing.T) {
+		var buffer bytes.Buffer
+		test.AssertNoError(write(&buffer, csvFormat, listResult), t)
+		expected := "id,name\nD000000125,General Electric\nD000070392,\"Goldman, Sachs & Co\"\n"
+		test.AssertStringMatches(buffer.String(), expected, t)
+	})
+	t.Run("Writes the result's value as JSON", func(t *testing.T) {
+		var buffer bytes.Buffer
+		test.AssertNoError(write(&buffer, jsonFormat, listResult), t)
+		var decoded []string
+		test.AssertNoError(json.Unmarshal(buffer.Bytes(), &decoded), t)
+		test.AssertSliceLength(len(decoded), 2, t)
+	})
+}
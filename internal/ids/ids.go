@@ -0,0 +1,10 @@
+package ids
+
+import "regexp"
+
+var cidPattern = regexp.MustCompile(`^N\d{8}$`)
+
+// Reports whether the provided string is a CRP candidate ID (an N followed by eight digits, e.g. N00007360).
+func IsCID(s string) bool {
+	return cidPattern.MatchString(s)
+}
@@ -0,0 +1,18 @@
+package ids
+
+import "testing"
+
+func TestIsCID(t *testing.T) {
+	t.Run("Accepts a valid CID", func(t *testing.T) {
+		if !IsCID("N00007360") {
+			t.Error("Wanted N00007360 to be a valid CID")
+		}
+	})
+	t.Run("Rejects malformed CIDs", func(t *testing.T) {
+		for _, id := range []string{"", "N0000736", "N000073600", "n00007360", "X00007360", "N0000736A"} {
+			if IsCID(id) {
+				t.Errorf("Wanted %q to be an invalid CID", id)
+			}
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// The number of calls a batch makes at once unless BatchOptions.Concurrency says otherwise.
+const DefaultBatchConcurrency int = 4
+
+// Configures a batch of calls.
+type BatchOptions struct {
+	Concurrency       int                          // Calls in flight at once; defaults to DefaultBatchConcurrency
+	RequestsPerSecond float64                      // Most calls to start per second; 0 means no limit
+	OnProgress        func(progress BatchProgress) // Optional; called after each call finishes, never concurrently
+}
+
+// How far through a batch is.
+type BatchProgress struct {
+	Total     int // Calls in the batch
+	Completed int // Calls finished so far, successful or not
+	Failed    int // Calls finished so far that returned an error
+}
+
+// The outcome of one call in a batch.
+type BatchResult[R any, T any] struct {
+	Request R
+	Value   T
+	Err     error
+}
+
+// Returned alongside a batch's results when any of its calls failed. Each failed call's error is also on its
+// BatchResult.
+type BatchError struct {
+	Total  int
+	Failed []int   // Indexes of the failed calls in the batch
+	Errors []error // Errors of the failed calls, in the same order as Failed
+}
+
+func (b *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d calls in batch failed; first error: %v", len(b.Failed), b.Total, b.Errors[0])
+}
+
+func (b *BatchError) Unwrap() []error {
+	return b.Errors
+}
+
+/*
+Calls the provided function once for each request, a few at a time, and returns each call's result in the same order as
+the requests. If any calls fail, a *BatchError listing them is returned along with the results, so one failure doesn't
+lose the rest of the batch. Calls that haven't started when ctx is canceled fail with ctx's error.
+
+The Batch* functions in this package use RunBatch for the most common batches; you can use it directly for any other
+client method:
+
+	results, err := client.RunBatch(ctx, requests, openSecretsClient.GetCandidateTopSectorDetails, client.BatchOptions{})
+*/
+func RunBatch[R any, T any](ctx context.Context, requests []R, call func(context.Context, R) (T, error), options BatchOptions) ([]BatchResult[R, T], error) {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]BatchResult[R, T], len(requests))
+	for i, request := range requests {
+		results[i].Request = request
+	}
+
+	var limiter <-chan time.Time
+	if options.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / options.RequestsPerSecond))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	indexes := make(chan int)
+	var progressMutex sync.Mutex
+	progress := BatchProgress{Total: len(requests)}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(requests); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i].Value, results[i].Err = callWhenAllowed(ctx, limiter, results[i].Request, call)
+
+				progressMutex.Lock()
+				progress.Completed++
+				if results[i].Err != nil {
+					progress.Failed++
+				}
+				if options.OnProgress != nil {
+					options.OnProgress(progress)
+				}
+				progressMutex.Unlock()
+			}
+		}()
+	}
+
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var batchError *BatchError
+	for i, result := range results {
+		if result.Err != nil {
+			if batchError == nil {
+				batchError = &BatchError{Total: len(requests)}
+			}
+			batchError.Failed = append(batchError.Failed, i)
+			batchError.Errors = append(batchError.Errors, result.Err)
+		}
+	}
+	if batchError != nil {
+		return results, batchError
+	}
+	return results, nil
+}
+
+// Waits for the rate limiter (if there is one) and then makes the call, unless ctx is done first.
+func callWhenAllowed[R any, T any](ctx context.Context, limiter <-chan time.Time, request R, call func(context.Context, R) (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if limiter != nil {
+		select {
+		case <-limiter:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	return call(ctx, request)
+}
+
+// Gets the summary for each request with RunBatch.
+func BatchGetCandidateSummaries(ctx context.Context, client OpenSecretsClient, requests []models.CandidateSummaryRequest, options BatchOptions) ([]BatchResult[models.CandidateSummaryRequest, models.CandidateSummary], error) {
+	return RunBatch(ctx, requests, client.GetCandidateSummary, options)
+}
+
+// Gets the top industries for each request with RunBatch.
+func BatchGetCandidateIndustries(ctx context.Context, client OpenSecretsClient, requests []models.CandidateIndustriesRequest, options BatchOptions) ([]BatchResult[models.CandidateIndustriesRequest, models.CandidateIndustriesSummary], error) {
+	return RunBatch(ctx, requests, client.GetCandidateIndustries, options)
+}
+
+// Gets the top contributors for each request with RunBatch.
+func BatchGetCandidateContributors(ctx context.Context, client OpenSecretsClient, requests []models.CandidateContributorsRequest, options BatchOptions) ([]BatchResult[models.CandidateContributorsRequest, models.CandidateContributorSummary], error) {
+	return RunBatch(ctx, requests, client.GetCandidateContributors, options)
+}
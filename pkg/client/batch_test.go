@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func TestRunBatch(t *testing.T) {
+	t.Run("Returns each call's result in request order", func(t *testing.T) {
+		requests := []int{1, 2, 3, 4, 5, 6, 7}
+		results, err := RunBatch(context.Background(), requests, func(ctx context.Context, request int) (int, error) {
+			time.Sleep(time.Millisecond * time.Duration(7-request))
+			return request * 10, nil
+		}, BatchOptions{Concurrency: 3})
+
+		test.AssertNoError(err, t)
+		for i, result := range results {
+			test.AssertIntMatches(result.Request, requests[i], t)
+			test.AssertIntMatches(result.Value, requests[i]*10, t)
+		}
+	})
+	t.Run("Never has more calls in flight than the concurrency limit", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		_, err := RunBatch(context.Background(), make([]int, 20), func(ctx context.Context, request int) (int, error) {
+			current := inFlight.Add(1)
+			for {
+				max := maxInFlight.Load()
+				if current <= max || maxInFlight.CompareAndSwap(max, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			inFlight.Add(-1)
+			return 0, nil
+		}, BatchOptions{Concurrency: 2})
+
+		test.AssertNoError(err, t)
+		if maxInFlight.Load() > 2 {
+			t.Errorf("Wanted at most 2 calls in flight but saw %d", maxInFlight.Load())
+		}
+	})
+	t.Run("Reports partial failures without losing the successful results", func(t *testing.T) {
+		failure := errors.New("boom")
+		results, err := RunBatch(context.Background(), []int{1, 2, 3, 4}, func(ctx context.Context, request int) (int, error) {
+			if request%2 == 0 {
+				return 0, failure
+			}
+			return request, nil
+		}, BatchOptions{})
+
+		var batchError *BatchError
+		if !errors.As(err, &batchError) {
+			t.Fatalf("Wanted a *BatchError but got %v", err)
+		}
+		test.AssertSliceLength(len(batchError.Failed), 2, t)
+		test.AssertIntMatches(batchError.Failed[0], 1, t)
+		test.AssertIntMatches(batchError.Failed[1], 3, t)
+		if !errors.Is(err, failure) {
+			t.Error("Wanted the batch error to wrap the calls' errors")
+		}
+		test.AssertIntMatches(results[2].Value, 3, t)
+		test.AssertErrorExists(results[3].Err, t)
+	})
+	t.Run("Calls the progress callback after each call", func(t *testing.T) {
+		var updates []BatchProgress
+		var mutex sync.Mutex
+		RunBatch(context.Background(), []int{1, 2, 3}, func(ctx context.Context, request int) (int, error) {
+			if request == 2 {
+				return 0, errors.New("boom")
+			}
+			return request, nil
+		}, BatchOptions{OnProgress: func(progress BatchProgress) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			updates = append(updates, progress)
+		}})
+
+		test.AssertSliceLength(len(updates), 3, t)
+		last := updates[2]
+		test.AssertIntMatches(last.Total, 3, t)
+		test.AssertIntMatches(last.Completed, 3, t)
+		test.AssertIntMatches(last.Failed, 1, t)
+	})
+	t.Run("Limits how fast calls start", func(t *testing.T) {
+		start := time.Now()
+		_, err := RunBatch(context.Background(), make([]int, 3), func(ctx context.Context, request int) (int, error) {
+			return 0, nil
+		}, BatchOptions{Concurrency: 3, RequestsPerSecond: 50})
+
+		test.AssertNoError(err, t)
+		if elapsed := time.Since(start); elapsed < time.Millisecond*50 {
+			t.Errorf("Wanted 3 calls at 50 per second to take at least 60ms but they took %s", elapsed)
+		}
+	})
+	t.Run("Fails calls that haven't started once the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		results, err := RunBatch(ctx, make([]int, 10), func(ctx context.Context, request int) (int, error) {
+			calls.Add(1)
+			cancel()
+			return 0, nil
+		}, BatchOptions{Concurrency: 1})
+
+		test.AssertErrorExists(err, t)
+		test.AssertIntMatches(int(calls.Load()), 1, t)
+		if !errors.Is(results[9].Err, context.Canceled) {
+			t.Errorf("Wanted context.Canceled but got %v", results[9].Err)
+		}
+	})
+	t.Run("Handles an empty batch", func(t *testing.T) {
+		results, err := RunBatch(context.Background(), []int{}, func(ctx context.Context, request int) (int, error) {
+			return 0, nil
+		}, BatchOptions{})
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(results), 0, t)
+	})
+}
+
+func TestBatchGetCandidateSummaries(t *testing.T) {
+	t.Run("Gets a summary for each request", func(t *testing.T) {
+		httpClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			response := buildMockResponse(200, `{"response":{"summary":{"@attributes":{"cid":"`+req.URL.Query().Get("cid")+`"}}}}`)
+			return &response, nil
+		})
+		client := NewOpenSecretsClientWithHttpClient("hunter2", httpClient)
+		requests := []models.CandidateSummaryRequest{{Cid: "N00007360"}, {Cid: "N00033085"}, {Cid: "bad"}}
+
+		results, err := BatchGetCandidateSummaries(context.Background(), client, requests, BatchOptions{})
+
+		var batchError *BatchError
+		if !errors.As(err, &batchError) {
+			t.Fatalf("Wanted a *BatchError for the invalid request but got %v", err)
+		}
+		test.AssertStringMatches(results[0].Value.Cid, "N00007360", t)
+		test.AssertStringMatches(results[1].Value.Cid, "N00033085", t)
+		test.AssertErrorExists(results[2].Err, t)
+	})
+}
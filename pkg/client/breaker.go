@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Calls go through as normal
+	CircuitOpen                         // Calls fail fast with a *CircuitOpenError until the cooldown passes
+	CircuitHalfOpen                     // The cooldown has passed; one trial call is let through to test the API
+)
+
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(c))
+	}
+}
+
+// Defaults for CircuitBreakerSettings fields left at their zero value.
+const (
+	DefaultFailureThreshold int           = 5
+	DefaultCooldown         time.Duration = time.Second * 30
+)
+
+// Configures a CircuitBreaker.
+type CircuitBreakerSettings struct {
+	FailureThreshold int                                      // Consecutive failures that open the circuit
+	Cooldown         time.Duration                            // How long the circuit stays open before letting a trial call through
+	OnStateChange    func(from CircuitState, to CircuitState) // Optional; called whenever the state changes
+}
+
+/*
+A CircuitBreaker stops an OpenSecretsClient from calling an API that's failing. After FailureThreshold consecutive
+failures (transport errors, timeouts and 5xx status codes) it opens, and calls fail immediately with a
+*CircuitOpenError instead of waiting on the API. Once Cooldown has passed it lets a single trial call through: if that
+succeeds the circuit closes again, and if it fails the circuit reopens for another cooldown.
+
+Only failures that point at the API count. Validation errors, 4xx status codes, unparseable responses and canceled
+contexts neither open the circuit nor close it.
+
+A CircuitBreaker is thread safe. Pass it to a client with WithCircuitBreaker and keep a reference to it if you want to
+report its State, e.g. from a health check.
+*/
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mutex    sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool         // Whether the half-open trial call is in flight
+	changes  []transition // State changes not yet passed to OnStateChange
+}
+
+type transition struct {
+	from CircuitState
+	to   CircuitState
+}
+
+// Construct a CircuitBreaker, using DefaultFailureThreshold and DefaultCooldown for any settings left unset.
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultFailureThreshold
+	}
+	if settings.Cooldown <= 0 {
+		settings.Cooldown = DefaultCooldown
+	}
+	return &CircuitBreaker{settings: settings, now: time.Now}
+}
+
+// Fails calls fast with a *CircuitOpenError while the provided breaker is open.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(o *openSecretsClient) {
+		o.breaker = breaker
+	}
+}
+
+// Returned instead of calling the API while the circuit is open.
+type CircuitOpenError struct {
+	RetryAt time.Time // When the circuit will let a trial call through
+}
+
+func (c *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open; not calling OpenSecrets API until %s", c.RetryAt.Format(time.RFC3339))
+}
+
+// Returns the breaker's current state. An open circuit whose cooldown has passed reports CircuitHalfOpen.
+func (c *CircuitBreaker) State() CircuitState {
+	c.lock()
+	defer c.unlock()
+	c.checkCooldown()
+	return c.state
+}
+
+// Returns an error if a call shouldn't go through right now, and whether the call is the half-open trial. Every call
+// allow lets through must be followed by done.
+func (c *CircuitBreaker) allow() (bool, error) {
+	c.lock()
+	defer c.unlock()
+	c.checkCooldown()
+
+	switch c.state {
+	case CircuitOpen:
+		return false, &CircuitOpenError{RetryAt: c.openedAt.Add(c.settings.Cooldown)}
+	case CircuitHalfOpen:
+		if c.probing {
+			// Keep failing fast until the trial call finishes
+			return false, &CircuitOpenError{RetryAt: c.now()}
+		}
+		c.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// Records the outcome of a call allow let through.
+func (c *CircuitBreaker) done(probe bool, err error) {
+	c.lock()
+	defer c.unlock()
+
+	if probe {
+		c.probing = false
+	}
+
+	switch {
+	case err == nil:
+		// A call that was already in flight when the circuit opened doesn't close it; only the trial call can
+		if probe || c.state == CircuitClosed {
+			c.failures = 0
+			c.setState(CircuitClosed)
+		}
+	case isUpstreamFailure(err):
+		c.failures++
+		if probe || (c.state == CircuitClosed && c.failures >= c.settings.FailureThreshold) {
+			c.openedAt = c.now()
+			c.setState(CircuitOpen)
+		}
+	}
+}
+
+func (c *CircuitBreaker) lock() {
+	c.mutex.Lock()
+}
+
+// Unlocks the breaker, then passes any state changes to OnStateChange so it can safely call the breaker's methods.
+func (c *CircuitBreaker) unlock() {
+	changes := c.changes
+	c.changes = nil
+	c.mutex.Unlock()
+
+	if c.settings.OnStateChange != nil {
+		for _, change := range changes {
+			c.settings.OnStateChange(change.from, change.to)
+		}
+	}
+}
+
+// Moves an open circuit to half-open once its cooldown passes. Callers must hold the lock.
+func (c *CircuitBreaker) checkCooldown() {
+	if c.state == CircuitOpen && !c.now().Before(c.openedAt.Add(c.settings.Cooldown)) {
+		c.setState(CircuitHalfOpen)
+	}
+}
+
+// Callers must hold the lock.
+func (c *CircuitBreaker) setState(state CircuitState) {
+	if state == c.state {
+		return
+	}
+	c.changes = append(c.changes, transition{from: c.state, to: state})
+	c.state = state
+}
+
+// Reports whether err suggests the API (rather than the request or the caller) is the problem.
+func isUpstreamFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return statusError.StatusCode >= 500
+	}
+	return true
+}
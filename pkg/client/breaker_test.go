@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func newTestBreaker(settings CircuitBreakerSettings) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	breaker := NewCircuitBreaker(settings)
+	breaker.now = clock.Now
+	return breaker, clock
+}
+
+// An HTTP client whose responses can be switched between failing and succeeding.
+type switchableHttpClient struct {
+	statusCode int
+	err        error
+	requests   int
+}
+
+func (s *switchableHttpClient) Do(req *http.Request) (*http.Response, error) {
+	s.requests++
+	if s.err != nil {
+		return nil, s.err
+	}
+	response := buildMockResponse(s.statusCode, `{}`)
+	return &response, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("Opens after the configured number of consecutive failures and fails fast", func(t *testing.T) {
+		breaker, _ := newTestBreaker(CircuitBreakerSettings{FailureThreshold: 3, Cooldown: time.Minute})
+		httpClient := &switchableHttpClient{statusCode: 503}
+		client := newOpenSecretsClient("hunter2", httpClient, []Option{WithCircuitBreaker(breaker)})
+
+		for i := 0; i < 3; i++ {
+			_, err := client.GetLatestIndependentExpenditures(context.Background())
+			var statusError *StatusError
+			if !errors.As(err, &statusError) {
+				t.Fatalf("Wanted a *StatusError but got %v", err)
+			}
+		}
+		if breaker.State() != CircuitOpen {
+			t.Fatalf("Wanted the circuit open but it's %s", breaker.State())
+		}
+
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		var circuitOpenError *CircuitOpenError
+		if !errors.As(err, &circuitOpenError) {
+			t.Fatalf("Wanted a *CircuitOpenError but got %v", err)
+		}
+		test.AssertIntMatches(httpClient.requests, 3, t)
+	})
+	t.Run("Resets the failure count after a success", func(t *testing.T) {
+		breaker, _ := newTestBreaker(CircuitBreakerSettings{FailureThreshold: 2})
+		httpClient := &switchableHttpClient{err: errors.New("connection refused")}
+		client := newOpenSecretsClient("hunter2", httpClient, []Option{WithCircuitBreaker(breaker)})
+
+		client.GetLatestIndependentExpenditures(context.Background())
+		httpClient.err = nil
+		httpClient.statusCode = 200
+		client.GetLatestIndependentExpenditures(context.Background())
+		httpClient.err = errors.New("connection refused")
+		client.GetLatestIndependentExpenditures(context.Background())
+
+		if breaker.State() != CircuitClosed {
+			t.Errorf("Wanted the circuit closed but it's %s", breaker.State())
+		}
+	})
+	t.Run("Doesn't count 4xx responses or canceled contexts", func(t *testing.T) {
+		breaker, _ := newTestBreaker(CircuitBreakerSettings{FailureThreshold: 1})
+		client := newOpenSecretsClient("hunter2", &switchableHttpClient{statusCode: 404}, []Option{WithCircuitBreaker(breaker)})
+		client.GetLatestIndependentExpenditures(context.Background())
+
+		canceledClient := newOpenSecretsClient("hunter2", &switchableHttpClient{err: context.Canceled}, []Option{WithCircuitBreaker(breaker)})
+		canceledClient.GetLatestIndependentExpenditures(context.Background())
+
+		if breaker.State() != CircuitClosed {
+			t.Errorf("Wanted the circuit closed but it's %s", breaker.State())
+		}
+	})
+	t.Run("Lets one trial call through after the cooldown and closes if it succeeds", func(t *testing.T) {
+		var changes []string
+		breaker, clock := newTestBreaker(CircuitBreakerSettings{
+			FailureThreshold: 1,
+			Cooldown:         time.Minute,
+			OnStateChange: func(from CircuitState, to CircuitState) {
+				changes = append(changes, from.String()+"->"+to.String())
+			},
+		})
+		httpClient := &switchableHttpClient{statusCode: 500}
+		client := newOpenSecretsClient("hunter2", httpClient, []Option{WithCircuitBreaker(breaker)})
+
+		client.GetLatestIndependentExpenditures(context.Background())
+		clock.now = clock.now.Add(time.Minute)
+		if breaker.State() != CircuitHalfOpen {
+			t.Fatalf("Wanted the circuit half-open but it's %s", breaker.State())
+		}
+
+		httpClient.statusCode = 200
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertNoError(err, t)
+
+		if breaker.State() != CircuitClosed {
+			t.Errorf("Wanted the circuit closed but it's %s", breaker.State())
+		}
+		test.AssertSliceLength(len(changes), 3, t)
+		test.AssertStringMatches(changes[2], "half-open->closed", t)
+	})
+	t.Run("Reopens if the trial call fails", func(t *testing.T) {
+		breaker, clock := newTestBreaker(CircuitBreakerSettings{FailureThreshold: 2, Cooldown: time.Minute})
+		httpClient := &switchableHttpClient{statusCode: 502}
+		client := newOpenSecretsClient("hunter2", httpClient, []Option{WithCircuitBreaker(breaker)})
+
+		client.GetLatestIndependentExpenditures(context.Background())
+		client.GetLatestIndependentExpenditures(context.Background())
+		clock.now = clock.now.Add(time.Minute)
+		client.GetLatestIndependentExpenditures(context.Background())
+
+		if breaker.State() != CircuitOpen {
+			t.Errorf("Wanted the circuit open but it's %s", breaker.State())
+		}
+		test.AssertIntMatches(httpClient.requests, 3, t)
+	})
+	t.Run("Fails other calls fast while the trial call is in flight", func(t *testing.T) {
+		breaker, clock := newTestBreaker(CircuitBreakerSettings{FailureThreshold: 1, Cooldown: time.Minute})
+		breaker.done(false, errors.New("connection refused"))
+		clock.now = clock.now.Add(time.Minute)
+
+		probe, err := breaker.allow()
+		test.AssertNoError(err, t)
+		if !probe {
+			t.Fatal("Wanted the first call after the cooldown to be the trial call")
+		}
+		_, err = breaker.allow()
+		var circuitOpenError *CircuitOpenError
+		if !errors.As(err, &circuitOpenError) {
+			t.Errorf("Wanted a *CircuitOpenError but got %v", err)
+		}
+	})
+	t.Run("Isn't closed by a call that was in flight when it opened", func(t *testing.T) {
+		breaker, clock := newTestBreaker(CircuitBreakerSettings{FailureThreshold: 2, Cooldown: time.Minute})
+
+		inFlight, err := breaker.allow()
+		test.AssertNoError(err, t)
+		for i := 0; i < 2; i++ {
+			probe, _ := breaker.allow()
+			breaker.done(probe, errors.New("connection refused"))
+		}
+
+		breaker.done(inFlight, nil)
+		if breaker.State() != CircuitOpen {
+			t.Fatalf("Wanted the circuit to stay open but it's %s", breaker.State())
+		}
+
+		clock.now = clock.now.Add(time.Minute)
+		probe, err := breaker.allow()
+		test.AssertNoError(err, t)
+		breaker.done(false, nil)
+		if breaker.State() != CircuitHalfOpen {
+			t.Fatalf("Wanted the circuit to stay half-open until the trial call finishes but it's %s", breaker.State())
+		}
+		breaker.done(probe, nil)
+		if breaker.State() != CircuitClosed {
+			t.Errorf("Wanted the trial call to close the circuit but it's %s", breaker.State())
+		}
+	})
+	t.Run("Uses defaults for unset settings", func(t *testing.T) {
+		breaker := NewCircuitBreaker(CircuitBreakerSettings{})
+		test.AssertIntMatches(breaker.settings.FailureThreshold, DefaultFailureThreshold, t)
+		if breaker.settings.Cooldown != DefaultCooldown {
+			t.Errorf("Wanted cooldown %s but got %s", DefaultCooldown, breaker.settings.Cooldown)
+		}
+	})
+}
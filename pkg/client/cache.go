@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Configures a ResponseCache.
+type CacheSettings struct {
+	// Return cached results immediately, refreshing them in the background, instead of waiting to revalidate them
+	StaleWhileRevalidate bool
+	// With StaleWhileRevalidate, results older than this are revalidated before being returned. 0 means any age is fine.
+	MaxStale time.Duration
+	// The most results to keep; the least recently fetched are dropped past this. 0 means no limit.
+	MaxEntries int
+}
+
+/*
+A ResponseCache keeps the results of a client's calls along with the ETag and Last-Modified headers the server sent
+with them. When the client makes the same call again it sends If-None-Match/If-Modified-Since, and if the server
+responds 304 Not Modified, returns the cached result without downloading or parsing anything.
+
+By default a cached result is only used once the server confirms it's current. With StaleWhileRevalidate set, a cached
+result is returned straight away and the server is asked for a fresher one in the background, so the next call gets
+it. Only responses that carry an ETag or Last-Modified header are cached, unless StaleWhileRevalidate is set.
+
+Callers share cached results, so slices in them are shared too; copy them before modifying them. Only successful calls
+are cached. A ResponseCache is thread safe.
+*/
+type ResponseCache struct {
+	settings CacheSettings
+	now      func() time.Time
+
+	mutex        sync.Mutex
+	entries      map[string]*cacheEntry
+	revalidating map[string]bool
+}
+
+type cacheEntry struct {
+	value      interface{}
+	validators validators
+	fetchedAt  time.Time // When the server last sent or confirmed the value
+}
+
+// Headers from an earlier response that let the server answer 304 Not Modified if nothing's changed.
+type validators struct {
+	etag         string
+	lastModified string
+}
+
+func (v validators) empty() bool {
+	return v.etag == "" && v.lastModified == ""
+}
+
+func validatorsFromHeader(header http.Header) validators {
+	return validators{etag: header.Get("ETag"), lastModified: header.Get("Last-Modified")}
+}
+
+// Construct an empty ResponseCache.
+func NewResponseCache(settings CacheSettings) *ResponseCache {
+	return &ResponseCache{
+		settings:     settings,
+		now:          time.Now,
+		entries:      map[string]*cacheEntry{},
+		revalidating: map[string]bool{},
+	}
+}
+
+// Caches call results in the provided cache and revalidates them with conditional requests.
+func WithResponseCache(cache *ResponseCache) Option {
+	return func(o *openSecretsClient) {
+		o.cache = cache
+	}
+}
+
+// Returns the number of results in the cache.
+func (r *ResponseCache) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.entries)
+}
+
+// Drops every result from the cache.
+func (r *ResponseCache) Clear() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = map[string]*cacheEntry{}
+}
+
+// Returns the entry for the provided key, or nil if there isn't one.
+func (r *ResponseCache) get(key string) *cacheEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.entries[key]
+}
+
+// Returns the entry for the provided key if it can be served without waiting to revalidate it.
+func (r *ResponseCache) getStale(key string) (*cacheEntry, bool) {
+	if !r.settings.StaleWhileRevalidate {
+		return nil, false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, found := r.entries[key]
+	if !found || (r.settings.MaxStale > 0 && r.now().Sub(entry.fetchedAt) > r.settings.MaxStale) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Caches a freshly fetched value, if it's cacheable.
+func (r *ResponseCache) store(key string, value interface{}, validators validators) {
+	if validators.empty() && !r.settings.StaleWhileRevalidate {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries[key] = &cacheEntry{value: value, validators: validators, fetchedAt: r.now()}
+	r.evict()
+}
+
+// Records that the server confirmed the provided entry is still current.
+func (r *ResponseCache) confirm(key string, entry *cacheEntry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Replace rather than update the entry, since other calls may be reading it
+	r.entries[key] = &cacheEntry{value: entry.value, validators: entry.validators, fetchedAt: r.now()}
+}
+
+// Runs the provided revalidation in the background, unless one's already running for the key.
+func (r *ResponseCache) revalidate(key string, revalidation func()) {
+	r.mutex.Lock()
+	if r.revalidating[key] {
+		r.mutex.Unlock()
+		return
+	}
+	r.revalidating[key] = true
+	r.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			r.mutex.Lock()
+			delete(r.revalidating, key)
+			r.mutex.Unlock()
+		}()
+		revalidation()
+	}()
+}
+
+// Drops the least recently fetched entries until the cache is within MaxEntries. Callers must hold the mutex.
+func (r *ResponseCache) evict() {
+	for r.settings.MaxEntries > 0 && len(r.entries) > r.settings.MaxEntries {
+		var oldestKey string
+		var oldest *cacheEntry
+		for key, entry := range r.entries {
+			if oldest == nil || entry.fetchedAt.Before(oldest.fetchedAt) {
+				oldestKey, oldest = key, entry
+			}
+		}
+		delete(r.entries, oldestKey)
+	}
+}
+
+type revalidationKey struct{}
+
+// Returns a context for a background revalidation, which must go to the server rather than be served stale. It starts
+// from an empty context rather than the caller's, so the revalidation isn't traced, recorded or canceled as part of the
+// call that triggered it.
+func contextForRevalidation() context.Context {
+	return context.WithValue(context.Background(), revalidationKey{}, true)
+}
+
+func isRevalidation(ctx context.Context) bool {
+	revalidation, _ := ctx.Value(revalidationKey{}).(bool)
+	return revalidation
+}
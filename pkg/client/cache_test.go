@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// A fake server for one resource whose body and ETag can change, honoring If-None-Match.
+type etagServer struct {
+	mutex    sync.Mutex
+	body     string
+	etag     string
+	requests []*http.Request
+	served   chan struct{} // Receives after each request, if set
+}
+
+func (e *etagServer) Do(req *http.Request) (*http.Response, error) {
+	e.mutex.Lock()
+	defer func() {
+		e.mutex.Unlock()
+		if e.served != nil {
+			e.served <- struct{}{}
+		}
+	}()
+
+	e.requests = append(e.requests, req)
+	header := http.Header{}
+	if e.etag != "" {
+		header.Set("ETag", e.etag)
+		if req.Header.Get("If-None-Match") == e.etag {
+			return &http.Response{StatusCode: http.StatusNotModified, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+	}
+	return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(strings.NewReader(e.body))}, nil
+}
+
+func (e *etagServer) update(name string, etag string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.body = `{"response":{"summary":{"@attributes":{"cand_name":"` + name + `"}}}}`
+	e.etag = etag
+}
+
+func newETagServer(name string, etag string) *etagServer {
+	server := &etagServer{}
+	server.update(name, etag)
+	return server
+}
+
+var cacheTestRequest = models.CandidateSummaryRequest{Cid: "N00007360"}
+
+func TestResponseCache(t *testing.T) {
+	t.Run("Revalidates cached results and serves them on 304 Not Modified", func(t *testing.T) {
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		client, spanRecorder, _ := newInstrumentedClient(nil)
+		client.client = server
+		client.cache = NewResponseCache(CacheSettings{})
+
+		first, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+		second, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+
+		test.AssertStringMatches(second.CandidateName, first.CandidateName, t)
+		test.AssertSliceLength(len(server.requests), 2, t)
+		test.AssertStringMatches(server.requests[1].Header.Get("If-None-Match"), `"v1"`, t)
+
+		spans := spanRecorder.Ended()
+		test.AssertStringMatches(attributeMap(spans[1].Attributes())[CacheAttributeKey].AsString(), CacheOutcomeRevalidated, t)
+	})
+	t.Run("Replaces cached results when the server has a newer version", func(t *testing.T) {
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		client := newOpenSecretsClient("hunter2", server, []Option{WithResponseCache(NewResponseCache(CacheSettings{}))})
+
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		server.update("Pelosi, Nancy P", `"v2"`)
+		summary, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(summary.CandidateName, "Pelosi, Nancy P", t)
+
+		summary, err = client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(summary.CandidateName, "Pelosi, Nancy P", t)
+		test.AssertStringMatches(server.requests[2].Header.Get("If-None-Match"), `"v2"`, t)
+	})
+	t.Run("Sends If-Modified-Since for responses with Last-Modified", func(t *testing.T) {
+		var requests []*http.Request
+		lastModified := "Wed, 21 Oct 2015 07:28:00 GMT"
+		httpClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			header := http.Header{"Last-Modified": []string{lastModified}}
+			return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		})
+		client := newOpenSecretsClient("hunter2", httpClient, []Option{WithResponseCache(NewResponseCache(CacheSettings{}))})
+
+		client.GetLatestIndependentExpenditures(context.Background())
+		client.GetLatestIndependentExpenditures(context.Background())
+
+		test.AssertStringMatches(requests[1].Header.Get("If-Modified-Since"), lastModified, t)
+	})
+	t.Run("Doesn't cache responses without validators or failed calls", func(t *testing.T) {
+		cache := NewResponseCache(CacheSettings{})
+		client := newOpenSecretsClient("hunter2", newETagServer("Pelosi, Nancy", ""), []Option{WithResponseCache(cache)})
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+
+		failingClient := newOpenSecretsClient("hunter2", &mockHttpClient{mockResponse: buildMockResponse(500, "")}, []Option{WithResponseCache(cache)})
+		failingClient.GetLatestIndependentExpenditures(context.Background())
+
+		test.AssertIntMatches(cache.Len(), 0, t)
+	})
+	t.Run("Drops the least recently fetched results past MaxEntries", func(t *testing.T) {
+		cache := NewResponseCache(CacheSettings{MaxEntries: 1})
+		clock := &fakeClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+		cache.now = clock.Now
+
+		cache.store("first", 1, validators{etag: `"a"`})
+		clock.now = clock.now.Add(time.Second)
+		cache.store("second", 2, validators{etag: `"b"`})
+
+		test.AssertIntMatches(cache.Len(), 1, t)
+		if cache.get("second") == nil {
+			t.Error("Wanted the most recent result kept")
+		}
+	})
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	t.Run("Returns cached results immediately and refreshes them in the background", func(t *testing.T) {
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		client := newOpenSecretsClient("hunter2", server, []Option{WithResponseCache(NewResponseCache(CacheSettings{StaleWhileRevalidate: true}))})
+
+		_, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+
+		server.update("Pelosi, Nancy P", `"v2"`)
+		server.served = make(chan struct{})
+		stale, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(stale.CandidateName, "Pelosi, Nancy", t)
+
+		select {
+		case <-server.served:
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for the background refresh")
+		}
+		server.served = nil
+
+		waitFor(func() bool {
+			summary, _ := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+			return summary.CandidateName == "Pelosi, Nancy P"
+		}, t)
+	})
+	t.Run("Caches responses without validators", func(t *testing.T) {
+		server := newETagServer("Pelosi, Nancy", "")
+		client := newOpenSecretsClient("hunter2", server, []Option{WithResponseCache(NewResponseCache(CacheSettings{StaleWhileRevalidate: true}))})
+
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertIntMatches(client.cache.Len(), 1, t)
+	})
+	t.Run("Revalidates results older than MaxStale before returning them", func(t *testing.T) {
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		cache := NewResponseCache(CacheSettings{StaleWhileRevalidate: true, MaxStale: time.Hour})
+		clock := &fakeClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+		cache.now = clock.Now
+		client := newOpenSecretsClient("hunter2", server, []Option{WithResponseCache(cache)})
+
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		server.update("Pelosi, Nancy P", `"v2"`)
+		clock.now = clock.now.Add(time.Hour * 2)
+
+		summary, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(summary.CandidateName, "Pelosi, Nancy P", t)
+	})
+	t.Run("Doesn't trace background refreshes as part of the call that triggered them", func(t *testing.T) {
+		spanRecorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		client := newOpenSecretsClient("hunter2", server, []Option{
+			WithResponseCache(NewResponseCache(CacheSettings{StaleWhileRevalidate: true})),
+			WithTracerProvider(tracerProvider),
+		})
+
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+		client.GetCandidateSummary(ctx, cacheTestRequest)
+		parent.End()
+
+		waitFor(func() bool { return len(spanRecorder.Ended()) == 4 }, t)
+		children := 0
+		for _, span := range spanRecorder.Ended() {
+			if span.Parent().SpanID() == parent.SpanContext().SpanID() {
+				children++
+			}
+		}
+		test.AssertIntMatches(children, 1, t)
+	})
+}
+
+func TestNotModifiedWithoutCache(t *testing.T) {
+	t.Run("Returns a StatusError rather than parsing the empty body", func(t *testing.T) {
+		httpClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotModified, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+		client := newOpenSecretsClient("hunter2", httpClient, nil)
+
+		_, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		var statusError *StatusError
+		if !errors.As(err, &statusError) {
+			t.Fatalf("Wanted a *StatusError but got %v", err)
+		}
+		test.AssertIntMatches(statusError.StatusCode, http.StatusNotModified, t)
+	})
+}
+
+func waitFor(condition func() bool, t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second * 5)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -5,14 +5,18 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
-	"github.com/KiaFarhang/opensecrets/internal/parse"
+	"github.com/KiaFarhang/opensecrets/pkg/decode"
 	"github.com/KiaFarhang/opensecrets/pkg/models"
-	"github.com/go-playground/validator/v10"
+	"github.com/KiaFarhang/opensecrets/pkg/validation"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 /*
@@ -73,241 +77,306 @@ type structValidator interface {
 }
 
 type openSecretsClient struct {
-	client    OpenSecretsHttpClient
-	apiKey    string
-	validator structValidator
+	client       OpenSecretsHttpClient
+	apiKey       string
+	validator    structValidator
+	parseOptions []decode.Option
+	middleware   []Middleware
+	coalescer    *callGroup
+	breaker      *CircuitBreaker
+	keyPool      *KeyPool
+	cache        *ResponseCache
+	baseURL      string
+
+	maxResponseSize int64
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	telemetry      *telemetry
+	logger         *slog.Logger
 }
 
 // Construct an OpenSecretsClient with the provided API key and a default http.Client (with a timeout of 5 seconds).
-func NewOpenSecretsClient(apikey string) OpenSecretsClient {
-	return &openSecretsClient{apiKey: apikey, client: &http.Client{Timeout: time.Second * 5}, validator: validator.New()}
+func NewOpenSecretsClient(apikey string, options ...Option) OpenSecretsClient {
+	return newOpenSecretsClient(apikey, &http.Client{Timeout: time.Second * 5}, options)
 }
 
 // Construct an OpenSecretsClient with the provided API key and a custom HTTP client.
-func NewOpenSecretsClientWithHttpClient(apikey string, client OpenSecretsHttpClient) OpenSecretsClient {
-	return &openSecretsClient{apiKey: apikey, client: client, validator: validator.New()}
+func NewOpenSecretsClientWithHttpClient(apikey string, client OpenSecretsHttpClient, options ...Option) OpenSecretsClient {
+	return newOpenSecretsClient(apikey, client, options)
 }
 
-func (o *openSecretsClient) GetLegislators(ctx context.Context, request models.LegislatorsRequest) ([]models.Legislator, error) {
-
-	err := o.validator.Struct(request)
-
-	if err != nil {
-		return nil, err
+func newOpenSecretsClient(apikey string, client OpenSecretsHttpClient, options []Option) *openSecretsClient {
+	openSecretsClient := &openSecretsClient{apiKey: apikey, client: client, validator: validation.New(), maxResponseSize: DefaultMaxResponseSize}
+	for _, option := range options {
+		option(openSecretsClient)
 	}
-	url := buildLegislatorsURL(request, o.apiKey)
-
-	responseBody, err := o.makeGETRequest(ctx, url)
+	openSecretsClient.client = chainMiddleware(openSecretsClient.client, openSecretsClient.middleware)
+	openSecretsClient.telemetry = newTelemetry(openSecretsClient.tracerProvider, openSecretsClient.meterProvider)
+	return openSecretsClient
+}
 
-	if err != nil {
-		return nil, err
-	}
+func (o *openSecretsClient) GetLegislators(ctx context.Context, request models.LegislatorsRequest) ([]models.Legislator, error) {
+	buildURL := func(apiKey string) string { return buildLegislatorsURL(request, apiKey) }
 
-	return parse.ParseLegislatorsJSON(responseBody)
+	return get(ctx, o, CallInfo{Method: "GetLegislators", Request: request}, buildURL, decode.DecodeLegislators)
 }
 
 func (o *openSecretsClient) GetMemberPFDProfile(ctx context.Context, request models.MemberPFDRequest) (models.MemberProfile, error) {
-	err := o.validator.Struct(request)
-
-	if err != nil {
-		return models.MemberProfile{}, err
-	}
+	buildURL := func(apiKey string) string { return buildMemberPFDURL(request, apiKey) }
 
-	url := buildMemberPFDURL(request, o.apiKey)
-
-	responseBody, err := o.makeGETRequest(ctx, url)
-
-	if err != nil {
-		return models.MemberProfile{}, err
-	}
-
-	return parse.ParseMemberPFDJSON(responseBody)
+	return get(ctx, o, CallInfo{Method: "GetMemberPFDProfile", Request: request}, buildURL, decode.DecodeMemberPFD)
 }
 
 func (o *openSecretsClient) GetCandidateSummary(ctx context.Context, request models.CandidateSummaryRequest) (models.CandidateSummary, error) {
-	err := o.validator.Struct(request)
-
-	if err != nil {
-		return models.CandidateSummary{}, err
-	}
-
-	url := buildCandidateSummaryURL(request, o.apiKey)
+	buildURL := func(apiKey string) string { return buildCandidateSummaryURL(request, apiKey) }
 
-	responseBody, err := o.makeGETRequest(ctx, url)
-
-	if err != nil {
-		return models.CandidateSummary{}, nil
-	}
-
-	return parse.ParseCandidateSummaryJSON(responseBody)
+	return get(ctx, o, CallInfo{Method: "GetCandidateSummary", Request: request}, buildURL, decode.DecodeCandidateSummary)
 }
 
 func (o *openSecretsClient) GetCandidateContributors(ctx context.Context, request models.CandidateContributorsRequest) (models.CandidateContributorSummary, error) {
-	err := o.validator.Struct(request)
-
-	if err != nil {
-		return models.CandidateContributorSummary{}, err
-	}
-
-	url := buildCandidateContributorsURL(request, o.apiKey)
-
-	responseBody, err := o.makeGETRequest(ctx, url)
+	buildURL := func(apiKey string) string { return buildCandidateContributorsURL(request, apiKey) }
 
-	if err != nil {
-		return models.CandidateContributorSummary{}, err
-	}
-
-	return parse.ParseCandidateContributorsJSON(responseBody)
+	return get(ctx, o, CallInfo{Method: "GetCandidateContributors", Request: request}, buildURL, decode.DecodeCandidateContributors)
 }
 
 func (o *openSecretsClient) GetCandidateIndustries(ctx context.Context, request models.CandidateIndustriesRequest) (models.CandidateIndustriesSummary, error) {
-	err := o.validator.Struct(request)
-
-	if err != nil {
-		return models.CandidateIndustriesSummary{}, err
-	}
+	buildURL := func(apiKey string) string { return buildGetCandidateIndustriesURL(request, apiKey) }
 
-	url := buildGetCandidateIndustriesURL(request, o.apiKey)
-
-	responseBody, err := o.makeGETRequest(ctx, url)
-
-	if err != nil {
-		return models.CandidateIndustriesSummary{}, err
-	}
-
-	return parse.ParseCandidateIndustriesJSON(responseBody)
+	return get(ctx, o, CallInfo{Method: "GetCandidateIndustries", Request: request}, buildURL, decode.DecodeCandidateIndustries)
 }
 
 func (o *openSecretsClient) GetCandidateIndustryDetails(ctx context.Context, request models.CandidateIndustryDetailsRequest) (models.CandidateIndustryDetails, error) {
-	err := o.validator.Struct(request)
+	buildURL := func(apiKey string) string { return buildCandidateIndustryDetailsURL(request, apiKey) }
 
-	if err != nil {
-		return models.CandidateIndustryDetails{}, err
-	}
+	return get(ctx, o, CallInfo{Method: "GetCandidateIndustryDetails", Request: request}, buildURL, decode.DecodeCandidateIndustryDetails)
+}
 
-	url := buildCandidateIndustryDetailsURL(request, o.apiKey)
+func (o *openSecretsClient) GetCandidateTopSectorDetails(ctx context.Context, request models.CandidateTopSectorsRequest) (models.CandidateTopSectorDetails, error) {
+	buildURL := func(apiKey string) string { return buildCandidateTopSectorsURL(request, apiKey) }
 
-	responseBody, err := o.makeGETRequest(ctx, url)
+	return get(ctx, o, CallInfo{Method: "GetCandidateTopSectorDetails", Request: request}, buildURL, decode.DecodeCandidateTopSectors)
+}
 
-	if err != nil {
-		return models.CandidateIndustryDetails{}, err
-	}
+func (o *openSecretsClient) GetCommitteeFundraisingDetails(ctx context.Context, request models.FundraisingByCongressionalCommitteeRequest) (models.CommitteeFundraisingDetails, error) {
+	buildURL := func(apiKey string) string { return buildFundraisingByCongressionalCommitteeRequestURL(request, apiKey) }
 
-	return parse.ParseCandidateIndustryDetailsJSON(responseBody)
+	return get(ctx, o, CallInfo{Method: "GetCommitteeFundraisingDetails", Request: request}, buildURL, decode.DecodeFundraisingByCommittee)
 }
 
-func (o *openSecretsClient) GetCandidateTopSectorDetails(ctx context.Context, request models.CandidateTopSectorsRequest) (models.CandidateTopSectorDetails, error) {
-	err := o.validator.Struct(request)
+func (o *openSecretsClient) SearchForOrganization(ctx context.Context, request models.OrganizationSearch) ([]models.OrganizationSearchResult, error) {
+	buildURL := func(apiKey string) string { return buildOrganizationSearchURL(request, apiKey) }
 
-	if err != nil {
-		return models.CandidateTopSectorDetails{}, err
-	}
+	return get(ctx, o, CallInfo{Method: "SearchForOrganization", Request: request}, buildURL, decode.DecodeOrganizationSearch)
+}
 
-	url := buildCandidateTopSectorsURL(request, o.apiKey)
+func (o *openSecretsClient) GetOrganizationSummary(ctx context.Context, request models.OrganizationSummaryRequest) (models.OrganizationSummary, error) {
+	buildURL := func(apiKey string) string { return buildOrganizationSummaryURL(request, apiKey) }
 
-	responseBody, err := o.makeGETRequest(ctx, url)
+	return get(ctx, o, CallInfo{Method: "GetOrganizationSummary", Request: request}, buildURL, decode.DecodeOrganizationSummary)
+}
 
-	if err != nil {
-		return models.CandidateTopSectorDetails{}, err
-	}
+func (o *openSecretsClient) GetLatestIndependentExpenditures(ctx context.Context) ([]models.IndependentExpenditure, error) {
+	return get(ctx, o, CallInfo{Method: "GetLatestIndependentExpenditures", Request: nil}, buildIndependentExpendituresURL, decode.DecodeIndependentExpenditures)
+}
 
-	return parse.ParseCandidateTopSectorsJSON(responseBody)
+// Returned when the OpenSecrets API responds with a 4xx or 5xx status code, or with 304 Not Modified to a request the
+// client has no cached result for.
+type StatusError struct {
+	StatusCode int
 }
 
-func (o *openSecretsClient) GetCommitteeFundraisingDetails(ctx context.Context, request models.FundraisingByCongressionalCommitteeRequest) (models.CommitteeFundraisingDetails, error) {
-	err := o.validator.Struct(request)
+func (s *StatusError) Error() string {
+	return fmt.Sprintf("received %d status code calling OpenSecrets API", s.StatusCode)
+}
 
-	if err != nil {
-		return models.CommitteeFundraisingDetails{}, err
-	}
+// What the server sent back for a successful (or 304 Not Modified) GET request. The body must be closed.
+type rawResponse struct {
+	statusCode int
+	body       *responseBody
+	validators validators
+}
 
-	url := buildFundraisingByCongressionalCommitteeRequestURL(request, o.apiKey)
+// Validates the request described by info, makes a GET request on its behalf to the URL buildURL returns for an API
+// key, then decodes the response body with the provided function as it streams in.
+func get[T any](ctx context.Context, o *openSecretsClient, info CallInfo, buildURL func(apiKey string) string, decodeBody func(io.Reader, ...decode.Option) (T, error)) (result T, err error) {
+	ctx = contextWithCallInfo(ctx, info)
 
-	responseBody, err := o.makeGETRequest(ctx, url)
+	buildDefaultURL := buildURL
+	buildURL = func(apiKey string) string { return o.rebase(buildDefaultURL(apiKey)) }
 
-	if err != nil {
-		return models.CommitteeFundraisingDetails{}, err
-	}
+	// Redacted, this is the same whichever key the call ends up using
+	url := redactURL(buildURL(o.apiKey))
 
-	return parse.ParseFundraisingByCommitteeJSON(responseBody)
-}
+	ctx, observation := o.observe(ctx, info, url)
+	defer func() { observation.end(err) }()
 
-func (o *openSecretsClient) SearchForOrganization(ctx context.Context, request models.OrganizationSearch) ([]models.OrganizationSearchResult, error) {
-	err := o.validator.Struct(request)
+	if info.Request != nil {
+		err = o.validator.Struct(info.Request)
+		if err != nil {
+			return result, err
+		}
+	}
 
-	if err != nil {
-		return []models.OrganizationSearchResult{}, err
+	if o.cache != nil && !isRevalidation(ctx) {
+		if entry, found := o.cache.getStale(url); found {
+			observation.cached(CacheOutcomeStale)
+			o.cache.revalidate(url, func() {
+				get(contextForRevalidation(), o, info, buildURL, decodeBody)
+			})
+			return entry.value.(T), nil
+		}
 	}
 
-	url := buildOrganizationSearchURL(request, o.apiKey)
+	fetch := func(ctx context.Context) (interface{}, error) {
+		var entry *cacheEntry
+		var conditions validators
+		if o.cache != nil {
+			if entry = o.cache.get(url); entry != nil {
+				conditions = entry.validators
+			}
+		}
+
+		response, err := o.makeKeyedGETRequest(ctx, buildURL, conditions, observation)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil && response.statusCode == http.StatusNotModified {
+			response.body.Close()
+			observation.received(response)
+			observation.cached(CacheOutcomeRevalidated)
+			o.cache.confirm(url, entry)
+			return entry.value, nil
+		}
+
+		// Only possible if middleware made the request conditional; there's no cached result to fall back on
+		if response.statusCode == http.StatusNotModified {
+			response.body.Close()
+			observation.received(response)
+			return nil, &StatusError{StatusCode: response.statusCode}
+		}
+
+		parsed, err := decodeBody(response.body, o.parseOptions...)
+		response.body.Close()
+
+		observation.received(response)
+		observation.parsed(err)
+
+		if err == nil && o.cache != nil {
+			o.cache.store(url, parsed, response.validators)
+		}
+
+		return parsed, err
+	}
 
-	responseBody, err := o.makeGETRequest(ctx, url)
+	var value interface{}
+	if o.coalescer != nil {
+		var shared bool
+		// The redacted URL covers the method and every parameter, whichever API key the call uses
+		value, err, shared = o.coalescer.do(ctx, url, fetch)
+		if shared {
+			observation.coalesced()
+		}
+	} else {
+		value, err = fetch(ctx)
+	}
 
-	if err != nil {
-		return []models.OrganizationSearchResult{}, err
+	if value != nil {
+		result = value.(T)
 	}
 
-	return parse.ParseOrganizationSearchJSON(responseBody)
+	return result, err
 }
 
-func (o *openSecretsClient) GetOrganizationSummary(ctx context.Context, request models.OrganizationSummaryRequest) (models.OrganizationSummary, error) {
-	err := o.validator.Struct(request)
-
-	if err != nil {
-		return models.OrganizationSummary{}, err
+// Makes a GET request to the URL buildURL returns for the client's API key. With a key pool, the request is retried with
+// another key from the pool whenever the API says the key it used is over its limit.
+func (o *openSecretsClient) makeKeyedGETRequest(ctx context.Context, buildURL func(apiKey string) string, conditions validators, observation *observation) (rawResponse, error) {
+	if o.keyPool == nil {
+		return o.makeBreakerGuardedGETRequest(ctx, buildURL(o.apiKey), conditions)
 	}
 
-	url := buildOrganizationSummaryURL(request, o.apiKey)
+	for attempt := 1; ; attempt++ {
+		key, err := o.keyPool.take()
+		if err != nil {
+			return rawResponse{}, err
+		}
 
-	responseBody, err := o.makeGETRequest(ctx, url)
+		response, err := o.makeBreakerGuardedGETRequest(ctx, buildURL(key), conditions)
 
-	if err != nil {
-		return models.OrganizationSummary{}, err
-	}
+		var statusError *StatusError
+		if !errors.As(err, &statusError) || statusError.StatusCode != http.StatusTooManyRequests {
+			return response, err
+		}
 
-	return parse.ParseOrganizationSummaryJSON(responseBody)
+		o.keyPool.exhaust(key)
+		observation.retrying(attempt, err)
+	}
 }
 
-func (o *openSecretsClient) GetLatestIndependentExpenditures(ctx context.Context) ([]models.IndependentExpenditure, error) {
-	url := buildIndependentExpendituresURL(o.apiKey)
-
-	responseBody, err := o.makeGETRequest(ctx, url)
+// Makes a GET request to the provided URL unless the client's circuit breaker (if any) is open.
+func (o *openSecretsClient) makeBreakerGuardedGETRequest(ctx context.Context, url string, conditions validators) (rawResponse, error) {
+	if o.breaker == nil {
+		return o.makeGETRequest(ctx, url, conditions)
+	}
 
+	probe, err := o.breaker.allow()
 	if err != nil {
-		return []models.IndependentExpenditure{}, err
+		return rawResponse{}, err
 	}
 
-	return parse.ParseIndependentExpendituresJSON(responseBody)
+	response, err := o.makeGETRequest(ctx, url, conditions)
+	o.breaker.done(probe, err)
+
+	return response, err
 }
 
-func (o *openSecretsClient) makeGETRequest(ctx context.Context, url string) ([]byte, error) {
+// Makes a GET request to the provided URL, conditional on the provided validators if there are any.
+func (o *openSecretsClient) makeGETRequest(ctx context.Context, url string, conditions validators) (rawResponse, error) {
 	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return rawResponse{}, redactURLError(err)
 	}
 
 	// The API blocks requests without a user agent
 	request.Header.Set("User-Agent", "Golang")
+	// Setting this ourselves stops http.Transport decompressing for us, so responseBody handles it for any client
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	if conditions.etag != "" {
+		request.Header.Set("If-None-Match", conditions.etag)
+	}
+	if conditions.lastModified != "" {
+		request.Header.Set("If-Modified-Since", conditions.lastModified)
+	}
 
+	fetchedAt := time.Now()
 	response, err := o.client.Do(request)
 
 	if err != nil {
-		return nil, err
+		// http.Client's errors include the request URL, and with it the API key
+		return rawResponse{}, redactURLError(err)
 	}
 
-	defer response.Body.Close()
+	body := newResponseBody(response, o.maxResponseSize)
 
-	statusCode := response.StatusCode
-
-	if statusCode >= 400 {
-		return nil, fmt.Errorf("received %d status code calling OpenSecrets API", statusCode)
+	if recorders := recordersFromContext(ctx); len(recorders) > 0 {
+		body.record(func(bodyBytes []byte) {
+			record := newResponseRecord(url, response, bodyBytes, fetchedAt)
+			for _, recorder := range recorders {
+				recorder.add(record)
+			}
+		})
 	}
 
-	bodyAsBytes, err := io.ReadAll(response.Body)
+	statusCode := response.StatusCode
 
-	if err != nil {
-		return nil, err
+	if statusCode >= 400 {
+		// Closing reads the rest of the body, so a recorder keeps the error body for debugging.
+		body.Close()
+		return rawResponse{}, &StatusError{StatusCode: statusCode}
 	}
 
-	return bodyAsBytes, nil
+	return rawResponse{statusCode: statusCode, body: body, validators: validatorsFromHeader(response.Header)}, nil
 }
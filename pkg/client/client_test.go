@@ -6,14 +6,15 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/KiaFarhang/opensecrets/internal/parse"
 	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/decode"
 	"github.com/KiaFarhang/opensecrets/pkg/models"
-	"github.com/go-playground/validator/v10"
+	"github.com/KiaFarhang/opensecrets/pkg/validation"
 )
 
 type mockHttpClient struct {
@@ -34,16 +35,29 @@ func (m *mockValidator) Struct(s interface{}) error {
 
 func TestGetLegislators(t *testing.T) {
 	t.Run("Returns an error if the request passed is invalid", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.LegislatorsRequest{}
 		_, err := client.GetLegislators(context.Background(), request)
 		test.AssertErrorExists(err, t)
 	})
 }
 
+func TestRequestValidation(t *testing.T) {
+	t.Run("Returns structured field errors for an invalid request", func(t *testing.T) {
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
+		request := models.CandidateSummaryRequest{Cid: "Pelosi"}
+		_, err := client.GetCandidateSummary(context.Background(), request)
+		var fieldErrors validation.ValidationErrors
+		if !errors.As(err, &fieldErrors) {
+			t.Fatalf("Wanted validation.ValidationErrors but got %v", err)
+		}
+		test.AssertStringMatches(fieldErrors[0].Field, "Cid", t)
+	})
+}
+
 func TestGetMemberPFDProfile(t *testing.T) {
 	t.Run("Returns an error if the request passed is invalid", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.MemberPFDRequest{Year: 2020}
 		_, err := client.GetMemberPFDProfile(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -52,16 +66,21 @@ func TestGetMemberPFDProfile(t *testing.T) {
 
 func TestGetCandidateSummary(t *testing.T) {
 	t.Run("Returns an error if the request passed is invalid", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.CandidateSummaryRequest{Cycle: 2022}
 		_, err := client.GetCandidateSummary(context.Background(), request)
 		test.AssertErrorExists(err, t)
 	})
+	t.Run("Returns an error if the HTTP call fails", func(t *testing.T) {
+		client := openSecretsClient{client: &mockHttpClient{mockError: errors.New("fail")}, validator: &mockValidator{}}
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{})
+		test.AssertErrorMessage(err, "fail", t)
+	})
 }
 
 func TestGetCandidateContributors(t *testing.T) {
 	t.Run("Returns an error if the request passed is invaid", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.CandidateContributorsRequest{}
 		_, err := client.GetCandidateContributors(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -70,7 +89,7 @@ func TestGetCandidateContributors(t *testing.T) {
 
 func TestGetCandidateIndustries(t *testing.T) {
 	t.Run("Returns an error if the request passed is invalid", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.CandidateIndustriesRequest{}
 		_, err := client.GetCandidateIndustries(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -79,13 +98,13 @@ func TestGetCandidateIndustries(t *testing.T) {
 
 func TestGetCandidateIndustryDetails(t *testing.T) {
 	t.Run("Returns an error if the request doesn't have a CID", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.CandidateIndustryDetailsRequest{Ind: "K02"}
 		_, err := client.GetCandidateIndustryDetails(context.Background(), request)
 		test.AssertErrorExists(err, t)
 	})
 	t.Run("Returns an error if the request doesn't have an industry code", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.CandidateIndustryDetailsRequest{Cid: "N00007360"}
 		_, err := client.GetCandidateIndustryDetails(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -95,7 +114,7 @@ func TestGetCandidateIndustryDetails(t *testing.T) {
 
 func TestGetCandidateTopSectorDetails(t *testing.T) {
 	t.Run("Returns an error if the request doesn't have a CID", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.CandidateTopSectorsRequest{}
 		_, err := client.GetCandidateTopSectorDetails(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -104,13 +123,13 @@ func TestGetCandidateTopSectorDetails(t *testing.T) {
 
 func TestGetCommitteeFundraisingDetails(t *testing.T) {
 	t.Run("Returns an error if the request doesn't have a committee ID", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.FundraisingByCongressionalCommitteeRequest{Industry: "ABC"}
 		_, err := client.GetCommitteeFundraisingDetails(context.Background(), request)
 		test.AssertErrorExists(err, t)
 	})
 	t.Run("Returns an error if the request doesn't have an industry ID", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.FundraisingByCongressionalCommitteeRequest{Committee: "HARM"}
 		_, err := client.GetCommitteeFundraisingDetails(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -119,7 +138,7 @@ func TestGetCommitteeFundraisingDetails(t *testing.T) {
 
 func TestSearchForOrganization(t *testing.T) {
 	t.Run("Returns an error if the request doesn't have an org name", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.OrganizationSearch{}
 		_, err := client.SearchForOrganization(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -128,7 +147,7 @@ func TestSearchForOrganization(t *testing.T) {
 
 func TestGetOrganizationSummary(t *testing.T) {
 	t.Run("Returns an error if the request doesn't have an org ID", func(t *testing.T) {
-		client := openSecretsClient{client: &mockHttpClient{}, validator: validator.New()}
+		client := openSecretsClient{client: &mockHttpClient{}, validator: validation.New()}
 		request := models.OrganizationSummaryRequest{}
 		_, err := client.GetOrganizationSummary(context.Background(), request)
 		test.AssertErrorExists(err, t)
@@ -143,6 +162,20 @@ func TestMakeGETRequest(t *testing.T) {
 		test.AssertErrorExists(err, t)
 		test.AssertErrorMessage(err, "fail", t)
 	})
+	t.Run("Redacts the API key from transport errors", func(t *testing.T) {
+		testServer := httptest.NewServer(http.NotFoundHandler())
+		testServer.Close()
+		client := NewOpenSecretsClientWithHttpClient("hunter2", &http.Client{}, WithBaseURL(testServer.URL+"/api/"))
+
+		_, err := client.GetLegislators(context.Background(), models.LegislatorsRequest{Id: "TX"})
+		var urlError *url.Error
+		if !errors.As(err, &urlError) {
+			t.Fatalf("Wanted a *url.Error but got %v", err)
+		}
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("Wanted the API key redacted from the error but got %q", err.Error())
+		}
+	})
 	t.Run("Returns an error if the HTTP call is a >= 400 status code", func(t *testing.T) {
 		mockResponse := buildMockResponse(400, "")
 		client := openSecretsClient{client: &mockHttpClient{mockResponse: mockResponse}, validator: &mockValidator{}}
@@ -156,7 +189,7 @@ func TestMakeGETRequest(t *testing.T) {
 		client := openSecretsClient{client: &mockHttpClient{mockResponse: mockResponse}, validator: &mockValidator{}}
 		_, err := client.GetLegislators(context.Background(), models.LegislatorsRequest{})
 		test.AssertErrorExists(err, t)
-		wantedErrorMessage := parse.UnableToParseErrorMessage
+		wantedErrorMessage := decode.UnableToParseErrorMessage
 		test.AssertErrorMessage(err, wantedErrorMessage, t)
 	})
 	t.Run("returns an error if the context passed is canceled before the request completes", func(t *testing.T) {
@@ -177,7 +210,7 @@ func TestMakeGETRequest(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		_, err := openSecretsClient.makeGETRequest(ctx, testServer.URL)
+		_, err := openSecretsClient.makeGETRequest(ctx, testServer.URL, validators{})
 
 		test.AssertErrorExists(err, t)
 		if !strings.Contains(err.Error(), "context deadline exceeded") {
@@ -187,6 +220,27 @@ func TestMakeGETRequest(t *testing.T) {
 	})
 }
 
+func TestWithStrictParsing(t *testing.T) {
+	body := `{"response": {"organization": [{"@attributes": {"orgid": "D000000125", "orgname": "GE", "renamed": "x"}}]}}`
+	t.Run("Returns a SchemaDriftError and the parsed result when the response has drifted", func(t *testing.T) {
+		mockResponse := buildMockResponse(200, body)
+		client := newOpenSecretsClient("", &mockHttpClient{mockResponse: mockResponse}, []Option{WithStrictParsing()})
+		results, err := client.SearchForOrganization(context.Background(), models.OrganizationSearch{Name: "GE"})
+		var driftError *SchemaDriftError
+		if !errors.As(err, &driftError) {
+			t.Fatalf("Wanted a SchemaDriftError but got %v", err)
+		}
+		test.AssertSliceLength(len(results), 1, t)
+		test.AssertStringMatches(results[0].Extra["renamed"], "x", t)
+	})
+	t.Run("Ignores drift without the option", func(t *testing.T) {
+		mockResponse := buildMockResponse(200, body)
+		client := newOpenSecretsClient("", &mockHttpClient{mockResponse: mockResponse}, nil)
+		_, err := client.SearchForOrganization(context.Background(), models.OrganizationSearch{Name: "GE"})
+		test.AssertNoError(err, t)
+	})
+}
+
 func TestMakeGetRequestWithContext(t *testing.T) {
 
 }
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+Makes concurrent calls with the same method and parameters share a single HTTP request and parsed result. If your
+application asks for the same data from many goroutines at once (e.g. web handlers fetching the same candidate during
+a traffic spike) this keeps those calls from each reaching the API.
+
+Calls that share a result receive the same value, so slices in it (e.g. CandidateContributorSummary.Contributors) are
+shared between callers too; copy them before modifying them. A caller whose context is canceled stops waiting without
+affecting the others, and the shared request keeps running for the rest; it's canceled once every caller has stopped
+waiting.
+
+Every caller's Recorder (see ContextWithRecorder) gets a record of the shared response. The request itself is traced
+as part of the first caller's span, though; the other callers' spans are marked with CoalescedAttributeKey instead.
+*/
+func WithRequestCoalescing() Option {
+	return func(o *openSecretsClient) {
+		o.coalescer = &callGroup{}
+	}
+}
+
+// A callGroup deduplicates concurrent calls with the same key.
+type callGroup struct {
+	mutex sync.Mutex
+	calls map[string]*sharedCall
+}
+
+type sharedCall struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	waiters int                // Callers still waiting for the result, including the one that started the call
+	cancel  context.CancelFunc // Cancels the request once no one is waiting for it
+
+	recorders []*Recorder // Of every caller that's joined the call
+}
+
+// Runs fetch for the provided key, or waits for the call already in flight for that key. Returns fetch's results and
+// whether they came from another caller's call.
+func (g *callGroup) do(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error, bool) {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*sharedCall{}
+	}
+	if call, found := g.calls[key]; found {
+		call.waiters++
+		call.addRecorder(ctx)
+		g.mutex.Unlock()
+		return g.wait(ctx, key, call, true)
+	}
+
+	// Other callers may rely on this request, so it shouldn't stop just because this caller's context did; it's
+	// canceled instead once every caller has stopped waiting.
+	fetchContext, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &sharedCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+	call.addRecorder(ctx)
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	fetchContext = contextWithSharedRecorders(fetchContext, func() []*Recorder {
+		g.mutex.Lock()
+		defer g.mutex.Unlock()
+		return append([]*Recorder(nil), call.recorders...)
+	})
+
+	go func() {
+		defer cancel()
+		call.value, call.err = fetch(fetchContext)
+
+		g.mutex.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mutex.Unlock()
+
+		close(call.done)
+	}()
+
+	return g.wait(ctx, key, call, false)
+}
+
+// Waits for the call's result or for ctx to be done. The last caller to stop waiting cancels the call, and later calls
+// for the key start a new one.
+func (g *callGroup) wait(ctx context.Context, key string, call *sharedCall, shared bool) (interface{}, error, bool) {
+	select {
+	case <-call.done:
+		return call.value, call.err, shared
+	case <-ctx.Done():
+		g.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mutex.Unlock()
+		return nil, ctx.Err(), shared
+	}
+}
+
+// Adds the Recorder attached to ctx, if any, to the ones the call records to. The callGroup's mutex must be held.
+func (c *sharedCall) addRecorder(ctx context.Context) {
+	if recorder, found := recorderFromContext(ctx); found {
+		c.recorders = append(c.recorders, recorder)
+	}
+}
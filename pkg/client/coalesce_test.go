@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const coalesceTestAPIKey string = "hunter2"
+
+// An HTTP client that blocks every request until released, counting the requests it receives.
+type blockingHttpClient struct {
+	requests atomic.Int32
+	release  chan struct{}
+	body     string
+}
+
+func newBlockingHttpClient(body string) *blockingHttpClient {
+	return &blockingHttpClient{release: make(chan struct{}), body: body}
+}
+
+func (b *blockingHttpClient) Do(req *http.Request) (*http.Response, error) {
+	b.requests.Add(1)
+	<-b.release
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(b.body))}, nil
+}
+
+// Waits until the call in flight for key has the provided number of callers waiting on it.
+func waitForWaiters(group *callGroup, key string, waiters int, t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		group.mutex.Lock()
+		call, found := group.calls[key]
+		ready := found && call.waiters == waiters
+		group.mutex.Unlock()
+		if ready {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d callers to join the call for %s", waiters, key)
+}
+
+func TestWithRequestCoalescing(t *testing.T) {
+	t.Run("Shares one request and result between concurrent identical calls", func(t *testing.T) {
+		httpClient := newBlockingHttpClient(`{"response":{"summary":{"@attributes":{"cand_name":"Pelosi, Nancy"}}}}`)
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing()})
+		request := models.CandidateSummaryRequest{Cid: "N00007360"}
+
+		const callers = 5
+		results := make([]models.CandidateSummary, callers)
+		errs := make([]error, callers)
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = client.GetCandidateSummary(context.Background(), request)
+			}(i)
+		}
+
+		waitForWaiters(client.coalescer, redactURL(buildCandidateSummaryURL(request, coalesceTestAPIKey)), callers, t)
+		close(httpClient.release)
+		wg.Wait()
+
+		test.AssertIntMatches(int(httpClient.requests.Load()), 1, t)
+		for i := 0; i < callers; i++ {
+			test.AssertNoError(errs[i], t)
+			test.AssertStringMatches(results[i].CandidateName, "Pelosi, Nancy", t)
+		}
+	})
+	t.Run("Doesn't share requests between calls with different parameters", func(t *testing.T) {
+		httpClient := &countingHttpClient{}
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing()})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+		_, err = client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360", Cycle: 2020})
+		test.AssertNoError(err, t)
+		_, err = client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+
+		test.AssertIntMatches(int(httpClient.requests.Load()), 3, t)
+	})
+	t.Run("Lets a caller stop waiting without failing the others", func(t *testing.T) {
+		httpClient := newBlockingHttpClient(`{"response":{"summary":{"@attributes":{"cand_name":"Pelosi, Nancy"}}}}`)
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing()})
+		request := models.CandidateSummaryRequest{Cid: "N00007360"}
+		key := redactURL(buildCandidateSummaryURL(request, coalesceTestAPIKey))
+
+		leaderContext, cancelLeader := context.WithCancel(context.Background())
+		leaderErr := make(chan error)
+		go func() {
+			_, err := client.GetCandidateSummary(leaderContext, request)
+			leaderErr <- err
+		}()
+		waitForWaiters(client.coalescer, key, 1, t)
+
+		followerResult := make(chan models.CandidateSummary)
+		go func() {
+			summary, _ := client.GetCandidateSummary(context.Background(), request)
+			followerResult <- summary
+		}()
+		waitForWaiters(client.coalescer, key, 2, t)
+
+		cancelLeader()
+		if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+			t.Errorf("Wanted the canceled caller to get context.Canceled but got %v", err)
+		}
+
+		close(httpClient.release)
+		test.AssertStringMatches((<-followerResult).CandidateName, "Pelosi, Nancy", t)
+	})
+	t.Run("Marks the spans of calls that shared a result", func(t *testing.T) {
+		spanRecorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+		httpClient := newBlockingHttpClient(`{}`)
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing(), WithTracerProvider(tracerProvider)})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				client.GetLatestIndependentExpenditures(context.Background())
+			}()
+		}
+		waitForWaiters(client.coalescer, redactURL(buildIndependentExpendituresURL(coalesceTestAPIKey)), 2, t)
+		close(httpClient.release)
+		wg.Wait()
+
+		coalesced := 0
+		for _, span := range spanRecorder.Ended() {
+			if attributeMap(span.Attributes())[CoalescedAttributeKey].AsBool() {
+				coalesced++
+			}
+		}
+		test.AssertIntMatches(coalesced, 1, t)
+	})
+	t.Run("Records the shared response to every caller's Recorder", func(t *testing.T) {
+		httpClient := newBlockingHttpClient(`{}`)
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing()})
+		key := redactURL(buildIndependentExpendituresURL(coalesceTestAPIKey))
+
+		leaderRecorder, followerRecorder := NewRecorder(), NewRecorder()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.GetLatestIndependentExpenditures(ContextWithRecorder(context.Background(), leaderRecorder))
+		}()
+		waitForWaiters(client.coalescer, key, 1, t)
+		go func() {
+			defer wg.Done()
+			client.GetLatestIndependentExpenditures(ContextWithRecorder(context.Background(), followerRecorder))
+		}()
+		waitForWaiters(client.coalescer, key, 2, t)
+		close(httpClient.release)
+		wg.Wait()
+
+		test.AssertSliceLength(len(leaderRecorder.Records()), 1, t)
+		test.AssertSliceLength(len(followerRecorder.Records()), 1, t)
+		test.AssertStringMatches(string(followerRecorder.Records()[0].Body), `{}`, t)
+	})
+	t.Run("Cancels the shared request once every caller stops waiting", func(t *testing.T) {
+		httpClient := &cancelableHttpClient{canceled: make(chan error, 1)}
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing()})
+		key := redactURL(buildIndependentExpendituresURL(coalesceTestAPIKey))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				client.GetLatestIndependentExpenditures(ctx)
+			}()
+		}
+		waitForWaiters(client.coalescer, key, 2, t)
+		cancel()
+		wg.Wait()
+
+		select {
+		case err := <-httpClient.canceled:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Wanted the shared request canceled but got %v", err)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for the shared request to be canceled")
+		}
+
+		client.coalescer.mutex.Lock()
+		defer client.coalescer.mutex.Unlock()
+		test.AssertIntMatches(len(client.coalescer.calls), 0, t)
+	})
+	t.Run("Doesn't record the shared request on a call that has already ended", func(t *testing.T) {
+		httpClient := newBlockingHttpClient(`{}`)
+		var buffer lockedBuffer
+		logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client := newOpenSecretsClient(coalesceTestAPIKey, httpClient, []Option{WithRequestCoalescing(), WithLogger(logger)})
+		key := redactURL(buildIndependentExpendituresURL(coalesceTestAPIKey))
+
+		leaderContext, cancelLeader := context.WithCancel(context.Background())
+		leaderDone := make(chan struct{})
+		go func() {
+			client.GetLatestIndependentExpenditures(leaderContext)
+			close(leaderDone)
+		}()
+		waitForWaiters(client.coalescer, key, 1, t)
+
+		followerDone := make(chan struct{})
+		go func() {
+			client.GetLatestIndependentExpenditures(context.Background())
+			close(followerDone)
+		}()
+		waitForWaiters(client.coalescer, key, 2, t)
+
+		cancelLeader()
+		<-leaderDone
+		close(httpClient.release)
+		<-followerDone
+
+		if strings.Contains(buffer.String(), "OpenSecrets response received") {
+			t.Error("Wanted the response left unrecorded after the call that made it ended")
+		}
+	})
+}
+
+// An HTTP client that blocks every request until its context is done, then sends the context's error.
+type cancelableHttpClient struct {
+	canceled chan error
+}
+
+func (c *cancelableHttpClient) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	c.canceled <- req.Context().Err()
+	return nil, req.Context().Err()
+}
+
+// A bytes.Buffer that's safe for concurrent use.
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.buffer.Write(p)
+}
+
+func (l *lockedBuffer) String() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.buffer.String()
+}
+
+// An HTTP client that responds immediately, counting the requests it receives.
+type countingHttpClient struct {
+	requests atomic.Int32
+}
+
+func (c *countingHttpClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// How a KeyPool picks the key for each call.
+type KeyRotation int
+
+const (
+	RoundRobin         KeyRotation = iota // Cycle through the available keys in order
+	MostRemainingQuota                    // Use whichever available key has the most calls left today
+)
+
+// The number of calls per day OpenSecrets allows each API key, and the default for KeyPoolSettings.DailyQuota.
+const DefaultDailyQuota int = 200
+
+// Configures a KeyPool.
+type KeyPoolSettings struct {
+	Rotation   KeyRotation
+	DailyQuota int // Calls each key can make per day before it's considered exhausted; defaults to DefaultDailyQuota
+}
+
+/*
+A KeyPool spreads an OpenSecretsClient's calls across several API keys. It tracks how many calls each key has made
+today and stops using a key once it reaches its daily quota, or as soon as the API responds to it with a 429 status
+code; the client then retries the call with another key. Usage and exhaustion reset at midnight UTC.
+
+Keys can be added and removed at runtime with AddKeys and RemoveKeys, and a KeyPool is thread safe, so you can update
+the pool a client is using without rebuilding the client.
+*/
+type KeyPool struct {
+	settings KeyPoolSettings
+	now      func() time.Time
+
+	mutex sync.Mutex
+	keys  []*pooledKey
+	next  int       // Index of the next key to try for round robin rotation
+	day   time.Time // Start of the UTC day usage is being counted for
+}
+
+type pooledKey struct {
+	key       string
+	used      int
+	exhausted bool
+}
+
+// A snapshot of one key's usage, as returned by KeyPool.Status.
+type KeyStatus struct {
+	Key       string
+	Used      int  // Calls made with the key today
+	Remaining int  // Calls left before the key reaches its daily quota
+	Exhausted bool // Whether the key is out of calls for today
+}
+
+// Returned when a call can't be made because every key in the client's pool is exhausted (or the pool is empty).
+type KeysExhaustedError struct {
+	ResetAt time.Time // When the pool's usage resets
+}
+
+func (k *KeysExhaustedError) Error() string {
+	return fmt.Sprintf("no OpenSecrets API keys available until %s", k.ResetAt.Format(time.RFC3339))
+}
+
+// Construct a KeyPool holding the provided keys.
+func NewKeyPool(settings KeyPoolSettings, keys ...string) *KeyPool {
+	if settings.DailyQuota <= 0 {
+		settings.DailyQuota = DefaultDailyQuota
+	}
+	pool := &KeyPool{settings: settings, now: time.Now}
+	pool.AddKeys(keys...)
+	return pool
+}
+
+// Makes the client take a key from the provided pool for each call, rather than using the key passed to its
+// constructor (which can be left empty).
+func WithKeyPool(pool *KeyPool) Option {
+	return func(o *openSecretsClient) {
+		o.keyPool = pool
+	}
+}
+
+// Adds the provided keys to the pool, skipping any it already holds.
+func (k *KeyPool) AddKeys(keys ...string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	for _, key := range keys {
+		if k.indexOf(key) == -1 {
+			k.keys = append(k.keys, &pooledKey{key: key})
+		}
+	}
+}
+
+// Removes the provided keys from the pool. Calls already using them finish as normal.
+func (k *KeyPool) RemoveKeys(keys ...string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	for _, key := range keys {
+		if index := k.indexOf(key); index != -1 {
+			k.keys = append(k.keys[:index], k.keys[index+1:]...)
+		}
+	}
+	if k.next >= len(k.keys) {
+		k.next = 0
+	}
+}
+
+// Returns the usage of every key in the pool, in the order they were added.
+func (k *KeyPool) Status() []KeyStatus {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.resetIfNewDay()
+
+	toReturn := make([]KeyStatus, len(k.keys))
+	for i, key := range k.keys {
+		toReturn[i] = KeyStatus{Key: key.key, Used: key.used, Remaining: k.remaining(key), Exhausted: key.exhausted}
+	}
+	return toReturn
+}
+
+// Picks a key for a call and counts the call against its quota.
+func (k *KeyPool) take() (string, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.resetIfNewDay()
+
+	var chosen *pooledKey
+	switch k.settings.Rotation {
+	case MostRemainingQuota:
+		for _, key := range k.keys {
+			if !key.exhausted && (chosen == nil || k.remaining(key) > k.remaining(chosen)) {
+				chosen = key
+			}
+		}
+	default:
+		for i := 0; i < len(k.keys); i++ {
+			key := k.keys[(k.next+i)%len(k.keys)]
+			if !key.exhausted {
+				chosen = key
+				k.next = (k.next + i + 1) % len(k.keys)
+				break
+			}
+		}
+	}
+
+	if chosen == nil {
+		return "", &KeysExhaustedError{ResetAt: k.day.AddDate(0, 0, 1)}
+	}
+
+	chosen.used++
+	if chosen.used >= k.settings.DailyQuota {
+		chosen.exhausted = true
+	}
+	return chosen.key, nil
+}
+
+// Stops using the provided key until usage resets, e.g. because the API said it's over its limit.
+func (k *KeyPool) exhaust(key string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if index := k.indexOf(key); index != -1 {
+		k.keys[index].exhausted = true
+	}
+}
+
+// Callers must hold the mutex.
+func (k *KeyPool) indexOf(key string) int {
+	for i, pooled := range k.keys {
+		if pooled.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Callers must hold the mutex.
+func (k *KeyPool) remaining(key *pooledKey) int {
+	if key.exhausted {
+		return 0
+	}
+	return k.settings.DailyQuota - key.used
+}
+
+// Clears usage and exhaustion once a new UTC day starts. Callers must hold the mutex.
+func (k *KeyPool) resetIfNewDay() {
+	now := k.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if today.Equal(k.day) {
+		return
+	}
+	k.day = today
+	for _, key := range k.keys {
+		key.used = 0
+		key.exhausted = false
+	}
+}
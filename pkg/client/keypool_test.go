@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func newTestKeyPool(settings KeyPoolSettings, keys ...string) (*KeyPool, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	pool := NewKeyPool(settings, keys...)
+	pool.now = clock.Now
+	return pool, clock
+}
+
+// An HTTP client that responds 429 to requests made with any of the provided keys, and 200 otherwise, remembering
+// which key each request used.
+func limitedKeysClient(limitedKeys []string, usedKeys *[]string) HttpClientFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		key := req.URL.Query().Get("apikey")
+		*usedKeys = append(*usedKeys, key)
+		for _, limited := range limitedKeys {
+			if key == limited {
+				response := buildMockResponse(http.StatusTooManyRequests, "")
+				return &response, nil
+			}
+		}
+		response := buildMockResponse(200, `{}`)
+		return &response, nil
+	}
+}
+
+func TestKeyPool(t *testing.T) {
+	t.Run("Rotates keys round robin", func(t *testing.T) {
+		pool, _ := newTestKeyPool(KeyPoolSettings{}, "one", "two", "three")
+		var usedKeys []string
+		client := newOpenSecretsClient("", limitedKeysClient(nil, &usedKeys), []Option{WithKeyPool(pool)})
+
+		for i := 0; i < 4; i++ {
+			_, err := client.GetLatestIndependentExpenditures(context.Background())
+			test.AssertNoError(err, t)
+		}
+
+		test.AssertStringMatches(strings.Join(usedKeys, ","), "one,two,three,one", t)
+	})
+	t.Run("Picks the key with the most remaining quota", func(t *testing.T) {
+		pool, _ := newTestKeyPool(KeyPoolSettings{Rotation: MostRemainingQuota, DailyQuota: 10}, "one", "two")
+		for _, wanted := range []string{"one", "two", "one", "two"} {
+			key, err := pool.take()
+			test.AssertNoError(err, t)
+			test.AssertStringMatches(key, wanted, t)
+		}
+
+		pool.AddKeys("three")
+		key, err := pool.take()
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(key, "three", t)
+
+		pool.exhaust("one")
+		key, err = pool.take()
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(key, "three", t)
+		key, err = pool.take()
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(key, "two", t)
+
+		status := pool.Status()
+		test.AssertIntMatches(status[0].Remaining, 0, t)
+		test.AssertIntMatches(status[1].Remaining, 7, t)
+	})
+	t.Run("Retries with another key when the API says a key is over its limit", func(t *testing.T) {
+		pool, _ := newTestKeyPool(KeyPoolSettings{}, "one", "two")
+		var usedKeys []string
+		client := newOpenSecretsClient("", limitedKeysClient([]string{"one"}, &usedKeys), []Option{WithKeyPool(pool)})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(strings.Join(usedKeys, ","), "one,two", t)
+
+		if !pool.Status()[0].Exhausted {
+			t.Error("Wanted the limited key marked exhausted")
+		}
+
+		_, err = client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(usedKeys[2], "two", t)
+	})
+	t.Run("Returns a *KeysExhaustedError once every key is exhausted", func(t *testing.T) {
+		pool, _ := newTestKeyPool(KeyPoolSettings{}, "one", "two")
+		var usedKeys []string
+		client := newOpenSecretsClient("", limitedKeysClient([]string{"one", "two"}, &usedKeys), []Option{WithKeyPool(pool)})
+
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+
+		var exhaustedError *KeysExhaustedError
+		if !errors.As(err, &exhaustedError) {
+			t.Fatalf("Wanted a *KeysExhaustedError but got %v", err)
+		}
+		if !exhaustedError.ResetAt.Equal(time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Wanted the error to reset at midnight UTC but got %s", exhaustedError.ResetAt)
+		}
+		test.AssertSliceLength(len(usedKeys), 2, t)
+		test.AssertStringMatches(errorType(err), ErrorTypeNoKeys, t)
+	})
+	t.Run("Stops using a key once it reaches its daily quota, until the next day", func(t *testing.T) {
+		pool, clock := newTestKeyPool(KeyPoolSettings{DailyQuota: 2}, "one")
+
+		pool.take()
+		pool.take()
+		_, err := pool.take()
+		test.AssertErrorExists(err, t)
+
+		clock.now = clock.now.Add(time.Hour * 12)
+		key, err := pool.take()
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(key, "one", t)
+	})
+	t.Run("Can be updated at runtime", func(t *testing.T) {
+		pool, _ := newTestKeyPool(KeyPoolSettings{}, "one")
+		var usedKeys []string
+		client := newOpenSecretsClient("", limitedKeysClient(nil, &usedKeys), []Option{WithKeyPool(pool)})
+
+		pool.AddKeys("two", "one")
+		pool.RemoveKeys("one")
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertNoError(err, t)
+
+		test.AssertSliceLength(len(pool.Status()), 1, t)
+		test.AssertStringMatches(usedKeys[0], "two", t)
+	})
+	t.Run("Returns a *KeysExhaustedError when the pool is empty", func(t *testing.T) {
+		pool, _ := newTestKeyPool(KeyPoolSettings{})
+		_, err := pool.take()
+
+		var exhaustedError *KeysExhaustedError
+		if !errors.As(err, &exhaustedError) {
+			t.Errorf("Wanted a *KeysExhaustedError but got %v", err)
+		}
+	})
+}
+
+func TestKeyPoolLogging(t *testing.T) {
+	t.Run("Logs retries without the keys involved", func(t *testing.T) {
+		pool := NewKeyPool(KeyPoolSettings{}, "hunter2", "hunter3")
+		var usedKeys []string
+		client, buffer := newLoggingClient(limitedKeysClient([]string{"hunter2"}, &usedKeys))
+		client.keyPool = pool
+
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertNoError(err, t)
+
+		if !strings.Contains(buffer.String(), "retrying with another key") {
+			t.Error("Wanted the retry logged")
+		}
+		if strings.Contains(buffer.String(), "hunter") {
+			t.Errorf("Logs contain an API key: %s", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+/*
+Logs each client method call to the provided logger:
+
+  - Debug when a request starts
+  - Debug once the response body has been read, with its status code and size
+  - Debug when a call succeeds, with its duration
+  - Info when a call's result comes from the client's ResponseCache
+  - Warn when a call is retried with another key from the client's key pool because the API said its key was over its
+    limit
+  - Warn when a call fails because of the request or a change in the API's response shape (validation errors, 4xx
+    status codes, schema drift in strict mode, canceled contexts, calls failed fast by an open circuit breaker)
+  - Error when a call fails for any other reason (5xx status codes, unparseable responses, transport errors)
+
+Every record includes the client method name and the request URL with the API key redacted.
+*/
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *openSecretsClient) {
+		o.logger = logger
+	}
+}
+
+func (o *observation) logStart() {
+	o.log(slog.LevelDebug, "OpenSecrets request started")
+}
+
+func (o *observation) logReceived(response rawResponse) {
+	o.log(slog.LevelDebug, "OpenSecrets response received", slog.Int("status", response.statusCode), slog.Int64("bytes", response.body.bytesRead()))
+}
+
+func (o *observation) logCached(outcome string) {
+	o.log(slog.LevelInfo, "OpenSecrets result served from cache", slog.String("cache", outcome))
+}
+
+func (o *observation) logRetry(attempt int, err error) {
+	o.log(slog.LevelWarn, "OpenSecrets API key over its limit; retrying with another key", slog.Int("attempt", attempt),
+		slog.String("error", redactURL(err.Error())))
+}
+
+func (o *observation) logEnd(duration time.Duration, err error) {
+	if err == nil {
+		o.log(slog.LevelDebug, "OpenSecrets request finished", slog.Duration("duration", duration))
+		return
+	}
+
+	errorType := errorType(err)
+	attributes := []slog.Attr{
+		slog.Duration("duration", duration),
+		slog.String("error_type", errorType),
+		slog.String("error", redactURL(err.Error())),
+	}
+
+	level := slog.LevelError
+	switch errorType {
+	case ErrorTypeValidation, ErrorTypeSchemaDrift, ErrorTypeCanceled, ErrorTypeCircuitOpen:
+		level = slog.LevelWarn
+	}
+
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		attributes = append(attributes, slog.Int("status", statusError.StatusCode))
+		if statusError.StatusCode < 500 {
+			level = slog.LevelWarn
+		}
+	}
+
+	o.log(level, "OpenSecrets request failed", attributes...)
+}
+
+func (o *observation) log(level slog.Level, message string, attributes ...slog.Attr) {
+	if o.logger == nil {
+		return
+	}
+	attributes = append([]slog.Attr{slog.String("method", o.info.Method), slog.String("url", o.url)}, attributes...)
+	o.logger.LogAttrs(o.ctx, level, message, attributes...)
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+const loggingTestAPIKey string = "hunter2"
+
+func newLoggingClient(httpClient OpenSecretsHttpClient) (*openSecretsClient, *bytes.Buffer) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return newOpenSecretsClient(loggingTestAPIKey, httpClient, []Option{WithLogger(logger)}), &buffer
+}
+
+func decodeLogRecords(buffer *bytes.Buffer, t *testing.T) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Couldn't decode log line %s", line)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Run("Logs the start, response and finish of a successful call", func(t *testing.T) {
+		client, buffer := newLoggingClient(&mockHttpClient{mockResponse: buildMockResponse(200, `{}`)})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+
+		records := decodeLogRecords(buffer, t)
+		test.AssertSliceLength(len(records), 3, t)
+		test.AssertStringMatches(records[0]["level"].(string), "DEBUG", t)
+		test.AssertStringMatches(records[1]["msg"].(string), "OpenSecrets response received", t)
+		test.AssertIntMatches(int(records[1]["status"].(float64)), 200, t)
+		test.AssertStringMatches(records[2]["level"].(string), "DEBUG", t)
+		test.AssertStringMatches(records[2]["method"].(string), "GetCandidateSummary", t)
+		if _, found := records[2]["duration"]; !found {
+			t.Error("Wanted a duration on the finish record")
+		}
+	})
+	t.Run("Logs results served from the cache at info level", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		client := newOpenSecretsClient(loggingTestAPIKey, server, []Option{WithLogger(logger), WithResponseCache(NewResponseCache(CacheSettings{}))})
+
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertStringMatches(buffer.String(), "", t)
+		client.GetCandidateSummary(context.Background(), cacheTestRequest)
+
+		records := decodeLogRecords(&buffer, t)
+		test.AssertSliceLength(len(records), 1, t)
+		test.AssertStringMatches(records[0]["msg"].(string), "OpenSecrets result served from cache", t)
+		test.AssertStringMatches(records[0]["cache"].(string), CacheOutcomeRevalidated, t)
+	})
+	t.Run("Logs 5xx responses at error level and 4xx responses at warn level", func(t *testing.T) {
+		for statusCode, wantedLevel := range map[int]string{503: "ERROR", 404: "WARN"} {
+			client, buffer := newLoggingClient(&mockHttpClient{mockResponse: buildMockResponse(statusCode, "")})
+
+			_, err := client.GetLatestIndependentExpenditures(context.Background())
+			test.AssertErrorExists(err, t)
+
+			records := decodeLogRecords(buffer, t)
+			last := records[len(records)-1]
+			test.AssertStringMatches(last["level"].(string), wantedLevel, t)
+			test.AssertStringMatches(last["error_type"].(string), ErrorTypeStatus, t)
+			test.AssertIntMatches(int(last["status"].(float64)), statusCode, t)
+		}
+	})
+	t.Run("Logs parse errors", func(t *testing.T) {
+		client, buffer := newLoggingClient(&mockHttpClient{mockResponse: buildMockResponse(200, "GARBAGE")})
+
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertErrorExists(err, t)
+
+		records := decodeLogRecords(buffer, t)
+		test.AssertStringMatches(records[len(records)-1]["error_type"].(string), ErrorTypeParse, t)
+	})
+	t.Run("Logs nothing without a logger", func(t *testing.T) {
+		client := newOpenSecretsClient(loggingTestAPIKey, &mockHttpClient{mockResponse: buildMockResponse(200, `{}`)}, nil)
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertNoError(err, t)
+	})
+}
+
+func TestLoggingRedactsAPIKey(t *testing.T) {
+	t.Run("Never logs the API key, even in transport errors that include the URL", func(t *testing.T) {
+		failingClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("Get %q: dial tcp: connection refused", req.URL.String())
+		})
+		client, buffer := newLoggingClient(failingClient)
+
+		_, err := client.GetLegislators(context.Background(), models.LegislatorsRequest{Id: "TX"})
+		test.AssertErrorExists(err, t)
+
+		if !strings.Contains(buffer.String(), RedactedAPIKey) {
+			t.Error("Wanted the logs to include the redacted URL")
+		}
+		if strings.Contains(buffer.String(), loggingTestAPIKey) {
+			t.Errorf("Logs contain the API key: %s", buffer.String())
+		}
+	})
+	t.Run("Never logs the API key on successful calls", func(t *testing.T) {
+		client, buffer := newLoggingClient(&mockHttpClient{mockResponse: buildMockResponse(200, `{}`)})
+
+		_, err := client.SearchForOrganization(context.Background(), models.OrganizationSearch{Name: "GE"})
+		test.AssertNoError(err, t)
+
+		if strings.Contains(buffer.String(), loggingTestAPIKey) {
+			t.Errorf("Logs contain the API key: %s", buffer.String())
+		}
+	})
+}
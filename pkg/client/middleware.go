@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Describes the client method an HTTP request is being made for.
+type CallInfo struct {
+	Method  string      // Name of the OpenSecretsClient method called (e.g. GetCandidateSummary)
+	Request interface{} // Request model passed to the method; nil for GetLatestIndependentExpenditures
+}
+
+type callInfoKey struct{}
+
+func contextWithCallInfo(ctx context.Context, info CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// Returns the CallInfo for the client method an HTTP request is being made for. Middleware can call this with the
+// request's context to find out which method and request model it's handling.
+func CallInfoFromContext(ctx context.Context) (CallInfo, bool) {
+	info, found := ctx.Value(callInfoKey{}).(CallInfo)
+	return info, found
+}
+
+// An adapter that lets an ordinary function act as an OpenSecretsHttpClient.
+type HttpClientFunc func(req *http.Request) (*http.Response, error)
+
+func (f HttpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+/*
+Middleware wraps the HTTP client the OpenSecretsClient sends requests through, so you can act on each request before
+it's sent and each response before it's parsed: adding headers for a proxy, logging, tagging requests with trace IDs,
+etc. Call next.Do to pass the request along:
+
+	func addTraceHeader(next client.OpenSecretsHttpClient) client.OpenSecretsHttpClient {
+		return client.HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := client.CallInfoFromContext(req.Context())
+			req.Header.Set("X-Trace-Id", newTraceId(info.Method))
+			return next.Do(req)
+		})
+	}
+
+Requests include the API key in their URL; take care not to log it.
+*/
+type Middleware func(next OpenSecretsHttpClient) OpenSecretsHttpClient
+
+// Adds middleware around the client's HTTP calls. Middleware runs in the order provided: the first one passed sees the
+// request first and the response last. Calling WithMiddleware more than once appends to the chain.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *openSecretsClient) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+func chainMiddleware(client OpenSecretsHttpClient, middleware []Middleware) OpenSecretsHttpClient {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		client = middleware[i](client)
+	}
+	return client
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	t.Run("Runs middleware in order around the HTTP call", func(t *testing.T) {
+		var calls []string
+		recordingMiddleware := func(name string) Middleware {
+			return func(next OpenSecretsHttpClient) OpenSecretsHttpClient {
+				return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name+" before")
+					response, err := next.Do(req)
+					calls = append(calls, name+" after")
+					return response, err
+				})
+			}
+		}
+		mockClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "client")
+			response := buildMockResponse(200, `{}`)
+			return &response, nil
+		})
+
+		client := NewOpenSecretsClientWithHttpClient("", mockClient, WithMiddleware(recordingMiddleware("first")), WithMiddleware(recordingMiddleware("second")))
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertNoError(err, t)
+
+		wanted := []string{"first before", "second before", "client", "second after", "first after"}
+		test.AssertSliceLength(len(calls), len(wanted), t)
+		for i := range wanted {
+			test.AssertStringMatches(calls[i], wanted[i], t)
+		}
+	})
+	t.Run("Lets middleware modify the request and see the method and request model", func(t *testing.T) {
+		var gotHeader string
+		var gotInfo CallInfo
+		mockClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			gotHeader = req.Header.Get("X-Proxy-Auth")
+			response := buildMockResponse(200, `{}`)
+			return &response, nil
+		})
+		authMiddleware := func(next OpenSecretsHttpClient) OpenSecretsHttpClient {
+			return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+				gotInfo, _ = CallInfoFromContext(req.Context())
+				req.Header.Set("X-Proxy-Auth", "token")
+				return next.Do(req)
+			})
+		}
+
+		client := NewOpenSecretsClientWithHttpClient("", mockClient, WithMiddleware(authMiddleware))
+		request := models.CandidateSummaryRequest{Cid: "N00007360"}
+		_, err := client.GetCandidateSummary(context.Background(), request)
+		test.AssertNoError(err, t)
+
+		test.AssertStringMatches(gotHeader, "token", t)
+		test.AssertStringMatches(gotInfo.Method, "GetCandidateSummary", t)
+		gotRequest, ok := gotInfo.Request.(models.CandidateSummaryRequest)
+		if !ok || gotRequest != request {
+			t.Errorf("Wanted request model %v but got %v", request, gotInfo.Request)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package client
+
+import "github.com/KiaFarhang/opensecrets/pkg/decode"
+
+// An Option customizes an OpenSecretsClient. Pass any number of them to NewOpenSecretsClient or
+// NewOpenSecretsClientWithHttpClient.
+type Option func(*openSecretsClient)
+
+// Returned by client methods in strict parsing mode when a response has attributes the models package doesn't know
+// about, or is missing ones it expects. The method's parsed result is returned alongside it.
+type SchemaDriftError = decode.SchemaDriftError
+
+// The unknown and missing attributes found on one kind of object in a response.
+type SchemaDrift = decode.Drift
+
+// Turns on strict parsing: methods return a *SchemaDriftError if the API's response doesn't match the models package,
+// so you notice when upstream data changes shape. Without it, unknown attributes are kept in each model's Extra map
+// and missing ones are left as zero values.
+func WithStrictParsing() Option {
+	return func(o *openSecretsClient) {
+		o.parseOptions = append(o.parseOptions, decode.Strict())
+	}
+}
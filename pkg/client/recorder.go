@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// The placeholder that replaces the API key in recorded URLs.
+const RedactedAPIKey string = "REDACTED"
+
+var apiKeyPattern = regexp.MustCompile(`(?i)(apikey=)[^&"\s]*`)
+
+// What the OpenSecrets API sent back for a single call.
+type ResponseRecord struct {
+	APIMethod  string        // OpenSecrets method called (e.g. getLegislators)
+	URL        string        // Request URL, with the API key replaced by RedactedAPIKey
+	StatusCode int           // HTTP status code of the response
+	Header     http.Header   // Response headers
+	Body       []byte        // Raw response body, before parsing
+	FetchedAt  time.Time     // When the request was sent
+	Duration   time.Duration // How long the server took to respond, including reading the body
+}
+
+/*
+A Recorder collects a ResponseRecord for every API call made with a context it's attached to (see ContextWithRecorder).
+Use one to see exactly what the server sent when a parsed struct looks wrong, or to keep an audit trail of calls.
+
+A Recorder is safe for concurrent use.
+*/
+type Recorder struct {
+	mutex   sync.Mutex
+	records []ResponseRecord
+}
+
+// Construct an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Returns a copy of every record collected so far, oldest first.
+func (r *Recorder) Records() []ResponseRecord {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	toReturn := make([]ResponseRecord, len(r.records))
+	copy(toReturn, r.records)
+	return toReturn
+}
+
+// Returns the most recent record, or false if nothing's been recorded.
+func (r *Recorder) Last() (ResponseRecord, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(r.records) == 0 {
+		return ResponseRecord{}, false
+	}
+	return r.records[len(r.records)-1], true
+}
+
+// Discards every record collected so far.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.records = nil
+}
+
+func (r *Recorder) add(record ResponseRecord) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.records = append(r.records, record)
+}
+
+type recorderKey struct{}
+
+// Returns a copy of the context with the Recorder attached. Every client call made with the returned context (or one
+// derived from it) is added to the Recorder.
+func ContextWithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+func recorderFromContext(ctx context.Context) (*Recorder, bool) {
+	recorder, found := ctx.Value(recorderKey{}).(*Recorder)
+	return recorder, found && recorder != nil
+}
+
+type sharedRecordersKey struct{}
+
+// Returns a copy of the context whose calls record to every Recorder the provided function returns, in place of any
+// attached with ContextWithRecorder.
+func contextWithSharedRecorders(ctx context.Context, recorders func() []*Recorder) context.Context {
+	return context.WithValue(ctx, sharedRecordersKey{}, recorders)
+}
+
+// Returns the Recorders a call made with the context should record to.
+func recordersFromContext(ctx context.Context) []*Recorder {
+	if recorders, found := ctx.Value(sharedRecordersKey{}).(func() []*Recorder); found {
+		return recorders()
+	}
+	if recorder, found := recorderFromContext(ctx); found {
+		return []*Recorder{recorder}
+	}
+	return nil
+}
+
+// Replaces the value of the apikey query parameter in the provided URL (or error message containing one) with
+// RedactedAPIKey.
+func redactURL(rawURL string) string {
+	return apiKeyPattern.ReplaceAllString(rawURL, "${1}"+RedactedAPIKey)
+}
+
+// Returns the error with the API key redacted from its URL if it's a *url.Error, as http.Client returns for transport
+// failures; otherwise returns it unchanged.
+func redactURLError(err error) error {
+	if urlError, ok := err.(*url.Error); ok {
+		return &url.Error{Op: urlError.Op, URL: redactURL(urlError.URL), Err: urlError.Err}
+	}
+	return err
+}
+
+func newResponseRecord(rawURL string, response *http.Response, body []byte, fetchedAt time.Time) ResponseRecord {
+	return ResponseRecord{
+		APIMethod:  apiMethodFromURL(rawURL),
+		URL:        redactURL(rawURL),
+		StatusCode: response.StatusCode,
+		Header:     response.Header.Clone(),
+		Body:       body,
+		FetchedAt:  fetchedAt,
+		Duration:   time.Since(fetchedAt),
+	}
+}
+
+func apiMethodFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Query().Get("method")
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("Records the raw response for calls made with its context", func(t *testing.T) {
+		body := `{"response": {"legislator": []}}`
+		mockResponse := buildMockResponse(200, body)
+		mockResponse.Header = http.Header{"Content-Type": []string{"application/json"}}
+		client := openSecretsClient{apiKey: "hunter2", client: &mockHttpClient{mockResponse: mockResponse}, validator: &mockValidator{}}
+
+		recorder := NewRecorder()
+		ctx := ContextWithRecorder(context.Background(), recorder)
+		_, err := client.GetLegislators(ctx, models.LegislatorsRequest{Id: "TX"})
+		test.AssertNoError(err, t)
+
+		record, found := recorder.Last()
+		if !found {
+			t.Fatal("Wanted a record but got none")
+		}
+		test.AssertStringMatches(record.APIMethod, "getLegislators", t)
+		test.AssertStringMatches(string(record.Body), body, t)
+		test.AssertIntMatches(record.StatusCode, 200, t)
+		test.AssertStringMatches(record.Header.Get("Content-Type"), "application/json", t)
+		if record.FetchedAt.IsZero() {
+			t.Error("Wanted FetchedAt to be set")
+		}
+		if strings.Contains(record.URL, "hunter2") {
+			t.Errorf("Wanted the API key redacted from %s", record.URL)
+		}
+	})
+	t.Run("Records error responses", func(t *testing.T) {
+		mockResponse := buildMockResponse(500, "upstream broke")
+		client := openSecretsClient{client: &mockHttpClient{mockResponse: mockResponse}, validator: &mockValidator{}}
+
+		recorder := NewRecorder()
+		_, err := client.GetLatestIndependentExpenditures(ContextWithRecorder(context.Background(), recorder))
+		test.AssertErrorExists(err, t)
+
+		records := recorder.Records()
+		test.AssertSliceLength(len(records), 1, t)
+		test.AssertIntMatches(records[0].StatusCode, 500, t)
+		test.AssertStringMatches(string(records[0].Body), "upstream broke", t)
+	})
+	t.Run("Records nothing for calls made without its context", func(t *testing.T) {
+		mockResponse := buildMockResponse(200, `{}`)
+		client := openSecretsClient{client: &mockHttpClient{mockResponse: mockResponse}, validator: &mockValidator{}}
+
+		recorder := NewRecorder()
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(recorder.Records()), 0, t)
+	})
+}
+
+func TestRedactURL(t *testing.T) {
+	t.Run("Replaces the API key wherever it appears in the query", func(t *testing.T) {
+		redacted := redactURL(buildCandidateSummaryURL(models.CandidateSummaryRequest{Cid: "N00007360"}, "hunter2"))
+		expectedUrl := baseUrl + "?method=candSummary&output=json&apikey=" + RedactedAPIKey + "&cid=N00007360"
+		test.AssertStringMatches(redacted, expectedUrl, t)
+	})
+}
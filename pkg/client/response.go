@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// The largest response body, after decompression, a client reads unless configured otherwise with WithMaxResponseSize.
+const DefaultMaxResponseSize int64 = 10 << 20 // 10 MiB
+
+// The most of an unread body Close reads so the connection can be reused. Anything bigger isn't worth reading just to
+// save a connection, so Close gives up on it.
+const maxDrainSize int64 = 4 << 10 // 4 KiB
+
+/*
+Sets the largest response body, in bytes after decompression, the client will read. Calls whose response is bigger fail
+with an error wrapping a *ResponseTooLargeError instead of reading the rest of it. Pass 0 to read bodies of any size.
+*/
+func WithMaxResponseSize(bytes int64) Option {
+	return func(o *openSecretsClient) {
+		o.maxResponseSize = bytes
+	}
+}
+
+// Returned (wrapped in a *decode.ReadError for successful status codes) when a response body exceeds the client's
+// maximum response size.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (r *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("OpenSecrets response body exceeded the maximum size of %d bytes", r.Limit)
+}
+
+/*
+A response body as the client reads it: decompressed if the server gzipped it, cut off at the client's maximum
+response size, and counted. If a Recorder is attached to the call, the body is also copied as it's read so the full
+response can be recorded when the body is closed.
+
+Decompression starts on the first Read, so responses nobody reads (like a 304 or a 5xx, which servers often send
+empty but still mark as gzipped) don't fail just because their body isn't valid gzip.
+*/
+type responseBody struct {
+	reader  io.Reader
+	gzipped bool  // Whether reader still needs wrapping in a gzip.Reader
+	err     error // Set if it couldn't be
+	closers []io.Closer
+	limit   int64
+	read    int64
+	copy    *bytes.Buffer // nil unless recording
+	onClose func(body []byte)
+	closed  bool
+}
+
+func newResponseBody(response *http.Response, limit int64) *responseBody {
+	return &responseBody{
+		reader:  response.Body,
+		gzipped: strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip"),
+		closers: []io.Closer{response.Body},
+		limit:   limit,
+	}
+}
+
+// Copies the body as it's read, then passes the copy to the provided function when the body is closed.
+func (r *responseBody) record(onClose func(body []byte)) {
+	r.copy = &bytes.Buffer{}
+	r.onClose = onClose
+}
+
+func (r *responseBody) Read(p []byte) (int, error) {
+	if r.gzipped {
+		r.gzipped = false
+		gzipReader, err := gzip.NewReader(r.reader)
+		if err != nil {
+			r.err = err
+		} else {
+			r.reader = gzipReader
+			r.closers = append([]io.Closer{gzipReader}, r.closers...)
+		}
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return 0, &ResponseTooLargeError{Limit: r.limit}
+	}
+	if r.copy != nil {
+		r.copy.Write(p[:n])
+	}
+	return n, err
+}
+
+// Reads whatever's left of the body (up to maxDrainSize) so the connection can be reused, then closes it.
+func (r *responseBody) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	// A body that's too large or fails mid-read has nothing more worth reading; the error was already returned to
+	// whoever was reading.
+	io.CopyN(io.Discard, r, maxDrainSize)
+
+	var err error
+	for _, closer := range r.closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	if r.onClose != nil {
+		r.onClose(r.copy.Bytes())
+	}
+
+	return err
+}
+
+// Returns how many bytes of the (decompressed) body have been read so far.
+func (r *responseBody) bytesRead() int64 {
+	if r.limit > 0 && r.read > r.limit {
+		return r.limit
+	}
+	return r.read
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/decode"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+const summaryResponseBody string = `{"response":{"summary":{"@attributes":{"cand_name":"Pelosi, Nancy"}}}}`
+
+func gzipped(body string, t *testing.T) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write([]byte(body))
+	test.AssertNoError(err, t)
+	test.AssertNoError(writer.Close(), t)
+	return buffer.Bytes()
+}
+
+func gzipResponseClient(body []byte, requests *[]*http.Request) HttpClientFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*requests = append(*requests, req)
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	t.Run("Asks for and decompresses gzipped responses", func(t *testing.T) {
+		var requests []*http.Request
+		client := newOpenSecretsClient("hunter2", gzipResponseClient(gzipped(summaryResponseBody, t), &requests), nil)
+
+		summary, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(summary.CandidateName, "Pelosi, Nancy", t)
+		test.AssertStringMatches(requests[0].Header.Get("Accept-Encoding"), "gzip", t)
+	})
+	t.Run("Records the decompressed body", func(t *testing.T) {
+		var requests []*http.Request
+		client := newOpenSecretsClient("hunter2", gzipResponseClient(gzipped(summaryResponseBody, t), &requests), nil)
+		recorder := NewRecorder()
+
+		_, err := client.GetCandidateSummary(ContextWithRecorder(context.Background(), recorder), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+
+		record, _ := recorder.Last()
+		test.AssertStringMatches(string(record.Body), summaryResponseBody, t)
+	})
+	t.Run("Returns an error for a body that claims to be gzipped but isn't", func(t *testing.T) {
+		var requests []*http.Request
+		client := newOpenSecretsClient("hunter2", gzipResponseClient([]byte(summaryResponseBody), &requests), nil)
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertErrorExists(err, t)
+	})
+	t.Run("Returns a StatusError for an empty 500 marked as gzipped", func(t *testing.T) {
+		client := newOpenSecretsClient("hunter2", emptyGzipResponseClient(http.StatusInternalServerError), nil)
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		var statusError *StatusError
+		if !errors.As(err, &statusError) {
+			t.Fatalf("Wanted a *StatusError but got %v", err)
+		}
+		test.AssertIntMatches(statusError.StatusCode, http.StatusInternalServerError, t)
+	})
+	t.Run("Serves a cached result for an empty 304 marked as gzipped", func(t *testing.T) {
+		server := newETagServer("Pelosi, Nancy", `"v1"`)
+		client := newOpenSecretsClient("hunter2", server, []Option{WithResponseCache(NewResponseCache(CacheSettings{}))})
+		_, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+
+		client.client = emptyGzipResponseClient(http.StatusNotModified)
+		summary, err := client.GetCandidateSummary(context.Background(), cacheTestRequest)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(summary.CandidateName, "Pelosi, Nancy", t)
+	})
+}
+
+// Responds to every request with the provided status code, an empty body and a gzip Content-Encoding header.
+func emptyGzipResponseClient(statusCode int) HttpClientFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+}
+
+func TestWithMaxResponseSize(t *testing.T) {
+	t.Run("Fails calls whose response is larger than the limit", func(t *testing.T) {
+		client := newOpenSecretsClient("hunter2", &mockHttpClient{mockResponse: buildMockResponse(200, summaryResponseBody)}, []Option{WithMaxResponseSize(16)})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+
+		var tooLargeError *ResponseTooLargeError
+		if !errors.As(err, &tooLargeError) {
+			t.Fatalf("Wanted a *ResponseTooLargeError but got %v", err)
+		}
+		var readError *decode.ReadError
+		if !errors.As(err, &readError) {
+			t.Errorf("Wanted the error wrapped in a *decode.ReadError but got %v", err)
+		}
+		test.AssertIntMatches(int(tooLargeError.Limit), 16, t)
+		test.AssertStringMatches(errorType(err), ErrorTypeTooLarge, t)
+	})
+	t.Run("Applies the limit to the decompressed size", func(t *testing.T) {
+		var requests []*http.Request
+		bomb := gzipped(`{"response":{"summary":{"@attributes":{"cand_name":"`+strings.Repeat("A", 1<<20)+`"}}}}`, t)
+		client := newOpenSecretsClient("hunter2", gzipResponseClient(bomb, &requests), []Option{WithMaxResponseSize(int64(len(bomb) * 2))})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+
+		var tooLargeError *ResponseTooLargeError
+		if !errors.As(err, &tooLargeError) {
+			t.Errorf("Wanted a *ResponseTooLargeError but got %v", err)
+		}
+	})
+	t.Run("Reads responses up to the limit", func(t *testing.T) {
+		client := newOpenSecretsClient("hunter2", &mockHttpClient{mockResponse: buildMockResponse(200, summaryResponseBody)}, []Option{WithMaxResponseSize(int64(len(summaryResponseBody)))})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Reads responses of any size when the limit is 0", func(t *testing.T) {
+		client := newOpenSecretsClient("hunter2", &mockHttpClient{mockResponse: buildMockResponse(200, summaryResponseBody)}, []Option{WithMaxResponseSize(0)})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360"})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Uses the default limit when unconfigured", func(t *testing.T) {
+		client := newOpenSecretsClient("hunter2", &mockHttpClient{}, nil)
+		test.AssertIntMatches(int(client.maxResponseSize), int(DefaultMaxResponseSize), t)
+	})
+}
+
+func TestResponseBody(t *testing.T) {
+	t.Run("Reads the rest of the body and closes it", func(t *testing.T) {
+		underlying := &trackingReadCloser{reader: strings.NewReader("unread")}
+		body := newResponseBody(&http.Response{Body: underlying}, 0)
+
+		test.AssertNoError(body.Close(), t)
+		if !underlying.closed {
+			t.Error("Wanted the underlying body closed")
+		}
+		test.AssertIntMatches(int(body.bytesRead()), len("unread"), t)
+	})
+	t.Run("Stops draining large bodies", func(t *testing.T) {
+		underlying := &trackingReadCloser{reader: strings.NewReader(strings.Repeat("x", int(maxDrainSize)*4))}
+		body := newResponseBody(&http.Response{Body: underlying}, 0)
+
+		test.AssertNoError(body.Close(), t)
+		if !underlying.closed {
+			t.Error("Wanted the underlying body closed")
+		}
+		test.AssertIntMatches(int(body.bytesRead()), int(maxDrainSize), t)
+	})
+}
+
+type trackingReadCloser struct {
+	reader io.Reader
+	closed bool
+}
+
+func (t *trackingReadCloser) Read(p []byte) (int, error) {
+	return t.reader.Read(p)
+}
+
+func (t *trackingReadCloser) Close() error {
+	t.closed = true
+	return nil
+}
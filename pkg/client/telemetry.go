@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/pkg/decode"
+	"github.com/KiaFarhang/opensecrets/pkg/validation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// The instrumentation scope name the client's tracer and meter use.
+const InstrumentationName string = "github.com/KiaFarhang/opensecrets/pkg/client"
+
+// Attribute keys the client records on spans and metrics.
+const (
+	MethodAttributeKey       attribute.Key = "opensecrets.method"        // Client method name, e.g. GetCandidateSummary
+	CidAttributeKey          attribute.Key = "opensecrets.cid"           // CID from the request, if it has one
+	CycleAttributeKey        attribute.Key = "opensecrets.cycle"         // Cycle from the request, if set
+	ParseOutcomeAttributeKey attribute.Key = "opensecrets.parse.outcome" // ok, schema_drift or error
+	CoalescedAttributeKey    attribute.Key = "opensecrets.coalesced"     // True when the call shared another call's request
+	CacheAttributeKey        attribute.Key = "opensecrets.cache"         // How a ResponseCache served the call, if it did
+	StatusCodeAttributeKey   attribute.Key = "http.response.status_code"
+	ErrorTypeAttributeKey    attribute.Key = "error.type"
+)
+
+// Values for the opensecrets.cache attribute.
+const (
+	CacheOutcomeRevalidated string = "revalidated" // The server responded 304 Not Modified to a conditional request
+	CacheOutcomeStale       string = "stale"       // Served from the cache while revalidating in the background
+)
+
+// Values for the error.type attribute.
+const (
+	ErrorTypeValidation  string = "validation"   // The request failed validation
+	ErrorTypeStatus      string = "status"       // The API responded with a 4xx or 5xx status code
+	ErrorTypeParse       string = "parse"        // The response body couldn't be parsed
+	ErrorTypeSchemaDrift string = "schema_drift" // Strict parsing found unknown or missing attributes
+	ErrorTypeCanceled    string = "canceled"     // The context was canceled or timed out
+	ErrorTypeCircuitOpen string = "circuit_open" // The circuit breaker failed the call fast without calling the API
+	ErrorTypeTooLarge    string = "too_large"    // The response body exceeded the client's maximum response size
+	ErrorTypeNoKeys      string = "no_keys"      // Every key in the client's key pool was exhausted
+	ErrorTypeTransport   string = "transport"    // The HTTP call itself failed
+)
+
+// Records spans for each client method call using the provided TracerProvider. The API key is never recorded.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *openSecretsClient) {
+		o.tracerProvider = provider
+	}
+}
+
+/*
+Records metrics for each client method call using the provided MeterProvider:
+
+  - opensecrets.client.duration: histogram of call latency in seconds, by method
+  - opensecrets.client.errors: count of failed calls, by method and error.type
+  - opensecrets.client.response.size: total bytes of response bodies received, by method
+*/
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(o *openSecretsClient) {
+		o.meterProvider = provider
+	}
+}
+
+type telemetry struct {
+	tracer       trace.Tracer
+	duration     metric.Float64Histogram
+	errors       metric.Int64Counter
+	responseSize metric.Int64Counter
+}
+
+var noopTelemetry = newTelemetry(nil, nil)
+
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) *telemetry {
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	meter := meterProvider.Meter(InstrumentationName)
+
+	// The metric API only returns errors for invalid instrument names and units; these are fixed and valid, and a
+	// failed instrument still hands back a usable no-op.
+	duration, _ := meter.Float64Histogram("opensecrets.client.duration", metric.WithUnit("s"),
+		metric.WithDescription("Duration of OpenSecrets client method calls"))
+	errorCount, _ := meter.Int64Counter("opensecrets.client.errors",
+		metric.WithDescription("Failed OpenSecrets client method calls"))
+	responseSize, _ := meter.Int64Counter("opensecrets.client.response.size", metric.WithUnit("By"),
+		metric.WithDescription("Bytes of OpenSecrets response bodies received"))
+
+	return &telemetry{
+		tracer:       tracerProvider.Tracer(InstrumentationName),
+		duration:     duration,
+		errors:       errorCount,
+		responseSize: responseSize,
+	}
+}
+
+func (o *openSecretsClient) getTelemetry() *telemetry {
+	if o.telemetry == nil {
+		return noopTelemetry
+	}
+	return o.telemetry
+}
+
+// Tracks a single client method call, recording spans, metrics and logs for it.
+type observation struct {
+	ctx       context.Context
+	telemetry *telemetry
+	logger    *slog.Logger
+	span      trace.Span
+	info      CallInfo
+	method    attribute.KeyValue
+	url       string // Request URL with the API key redacted
+	startedAt time.Time
+
+	// A coalesced request outlives the call that started it, so anything it records after that call has ended its
+	// span is dropped.
+	mutex sync.Mutex
+	ended bool
+}
+
+// Starts observing a call to the provided URL on behalf of the client method described by info. The URL is redacted
+// before it's stored, so the observation never holds the API key.
+func (o *openSecretsClient) observe(ctx context.Context, info CallInfo, url string) (context.Context, *observation) {
+	telemetry := o.getTelemetry()
+	method := MethodAttributeKey.String(info.Method)
+	attributes := append([]attribute.KeyValue{method}, requestAttributes(info.Request)...)
+
+	ctx, span := telemetry.tracer.Start(ctx, "opensecrets."+info.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+
+	observation := &observation{
+		ctx:       ctx,
+		telemetry: telemetry,
+		logger:    o.logger,
+		span:      span,
+		info:      info,
+		method:    method,
+		url:       redactURL(url),
+		startedAt: time.Now(),
+	}
+	observation.logStart()
+
+	return ctx, observation
+}
+
+// Records the server's response once its body has been read.
+func (o *observation) received(response rawResponse) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.ended {
+		return
+	}
+	o.span.SetAttributes(StatusCodeAttributeKey.Int(response.statusCode))
+	o.telemetry.responseSize.Add(o.ctx, response.body.bytesRead(), metric.WithAttributes(o.method))
+	o.logReceived(response)
+}
+
+// Records that the call shared the result of an identical call already in flight.
+func (o *observation) coalesced() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.ended {
+		return
+	}
+	o.span.SetAttributes(CoalescedAttributeKey.Bool(true))
+}
+
+// Records that the API said the key used for the provided attempt is over its limit, so the call is being retried with
+// another key.
+func (o *observation) retrying(attempt int, err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.ended {
+		return
+	}
+	o.span.AddEvent("retry", trace.WithAttributes(attribute.Int("opensecrets.attempt", attempt)))
+	o.logRetry(attempt, err)
+}
+
+// Records that the call's result came from the client's ResponseCache.
+func (o *observation) cached(outcome string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.ended {
+		return
+	}
+	o.span.SetAttributes(CacheAttributeKey.String(outcome))
+	o.logCached(outcome)
+}
+
+// Records the outcome of parsing the response body.
+func (o *observation) parsed(err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.ended {
+		return
+	}
+	outcome := "ok"
+	if err != nil {
+		outcome = errorType(err)
+		if outcome != ErrorTypeSchemaDrift {
+			outcome = "error"
+		}
+	}
+	o.span.SetAttributes(ParseOutcomeAttributeKey.String(outcome))
+}
+
+// Ends the span and records the call's duration and, if it failed, its error.
+func (o *observation) end(err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.ended {
+		return
+	}
+	o.ended = true
+
+	if err != nil {
+		errorTypeAttribute := ErrorTypeAttributeKey.String(errorType(err))
+
+		var statusError *StatusError
+		if errors.As(err, &statusError) {
+			o.span.SetAttributes(StatusCodeAttributeKey.Int(statusError.StatusCode))
+		}
+
+		o.span.SetAttributes(errorTypeAttribute)
+		o.span.SetStatus(codes.Error, redactURL(err.Error()))
+		o.telemetry.errors.Add(o.ctx, 1, metric.WithAttributes(o.method, errorTypeAttribute))
+	}
+
+	duration := time.Since(o.startedAt)
+	o.telemetry.duration.Record(o.ctx, duration.Seconds(), metric.WithAttributes(o.method))
+	o.span.End()
+	o.logEnd(duration, err)
+}
+
+func errorType(err error) string {
+	var validationErrors validation.ValidationErrors
+	var statusError *StatusError
+	var driftError *decode.SchemaDriftError
+	var circuitOpenError *CircuitOpenError
+	var tooLargeError *ResponseTooLargeError
+	var keysExhaustedError *KeysExhaustedError
+
+	switch {
+	case errors.As(err, &validationErrors):
+		return ErrorTypeValidation
+	case errors.As(err, &statusError):
+		return ErrorTypeStatus
+	case errors.As(err, &driftError):
+		return ErrorTypeSchemaDrift
+	case errors.As(err, &circuitOpenError):
+		return ErrorTypeCircuitOpen
+	case errors.As(err, &tooLargeError):
+		return ErrorTypeTooLarge
+	case errors.As(err, &keysExhaustedError):
+		return ErrorTypeNoKeys
+	case errors.Is(err, decode.ErrUnableToParse):
+		return ErrorTypeParse
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrorTypeCanceled
+	default:
+		return ErrorTypeTransport
+	}
+}
+
+// Returns CID and cycle attributes for request models that have them.
+func requestAttributes(request interface{}) []attribute.KeyValue {
+	value := reflect.ValueOf(request)
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var attributes []attribute.KeyValue
+	if cid := value.FieldByName("Cid"); cid.IsValid() && cid.Kind() == reflect.String {
+		attributes = append(attributes, CidAttributeKey.String(cid.String()))
+	}
+	if cycle := value.FieldByName("Cycle"); cycle.IsValid() && cycle.Kind() == reflect.Int && cycle.Int() != 0 {
+		attributes = append(attributes, CycleAttributeKey.Int64(cycle.Int()))
+	}
+	return attributes
+}
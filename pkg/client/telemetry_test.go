@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const telemetryTestAPIKey string = "hunter2"
+
+func newInstrumentedClient(mockResponse *mockHttpClient) (*openSecretsClient, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := newOpenSecretsClient(telemetryTestAPIKey, mockResponse, []Option{WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider)})
+	return client, spanRecorder, reader
+}
+
+func TestTelemetry(t *testing.T) {
+	t.Run("Records a span with the method, request, status and parse outcome", func(t *testing.T) {
+		client, spanRecorder, _ := newInstrumentedClient(&mockHttpClient{mockResponse: buildMockResponse(200, `{}`)})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{Cid: "N00007360", Cycle: 2022})
+		test.AssertNoError(err, t)
+
+		spans := spanRecorder.Ended()
+		test.AssertSliceLength(len(spans), 1, t)
+		span := spans[0]
+		test.AssertStringMatches(span.Name(), "opensecrets.GetCandidateSummary", t)
+
+		attributes := attributeMap(span.Attributes())
+		test.AssertStringMatches(attributes[MethodAttributeKey].AsString(), "GetCandidateSummary", t)
+		test.AssertStringMatches(attributes[CidAttributeKey].AsString(), "N00007360", t)
+		test.AssertIntMatches(int(attributes[CycleAttributeKey].AsInt64()), 2022, t)
+		test.AssertIntMatches(int(attributes[StatusCodeAttributeKey].AsInt64()), 200, t)
+		test.AssertStringMatches(attributes[ParseOutcomeAttributeKey].AsString(), "ok", t)
+	})
+	t.Run("Marks the span as an error with its type", func(t *testing.T) {
+		client, spanRecorder, _ := newInstrumentedClient(&mockHttpClient{mockResponse: buildMockResponse(503, "")})
+
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertErrorExists(err, t)
+
+		span := spanRecorder.Ended()[0]
+		if span.Status().Code != codes.Error {
+			t.Errorf("Wanted an error status but got %v", span.Status().Code)
+		}
+		attributes := attributeMap(span.Attributes())
+		test.AssertStringMatches(attributes[ErrorTypeAttributeKey].AsString(), ErrorTypeStatus, t)
+		test.AssertIntMatches(int(attributes[StatusCodeAttributeKey].AsInt64()), 503, t)
+	})
+	t.Run("Records validation failures", func(t *testing.T) {
+		client, spanRecorder, _ := newInstrumentedClient(&mockHttpClient{})
+
+		_, err := client.GetCandidateSummary(context.Background(), models.CandidateSummaryRequest{})
+		test.AssertErrorExists(err, t)
+
+		attributes := attributeMap(spanRecorder.Ended()[0].Attributes())
+		test.AssertStringMatches(attributes[ErrorTypeAttributeKey].AsString(), ErrorTypeValidation, t)
+	})
+	t.Run("Records latency, errors and bytes received", func(t *testing.T) {
+		client, _, reader := newInstrumentedClient(&mockHttpClient{mockResponse: buildMockResponse(200, `BAD JSON`)})
+
+		_, err := client.GetLatestIndependentExpenditures(context.Background())
+		test.AssertErrorExists(err, t)
+
+		var metrics metricdata.ResourceMetrics
+		test.AssertNoError(reader.Collect(context.Background(), &metrics), t)
+
+		byName := map[string]metricdata.Metrics{}
+		for _, scope := range metrics.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				byName[m.Name] = m
+			}
+		}
+
+		duration := byName["opensecrets.client.duration"].Data.(metricdata.Histogram[float64])
+		test.AssertIntMatches(int(duration.DataPoints[0].Count), 1, t)
+
+		errorCount := byName["opensecrets.client.errors"].Data.(metricdata.Sum[int64])
+		test.AssertIntMatches(int(errorCount.DataPoints[0].Value), 1, t)
+		errorType, _ := errorCount.DataPoints[0].Attributes.Value(ErrorTypeAttributeKey)
+		test.AssertStringMatches(errorType.AsString(), ErrorTypeParse, t)
+
+		responseSize := byName["opensecrets.client.response.size"].Data.(metricdata.Sum[int64])
+		test.AssertIntMatches(int(responseSize.DataPoints[0].Value), len("BAD JSON"), t)
+	})
+	t.Run("Never records the API key", func(t *testing.T) {
+		client, spanRecorder, _ := newInstrumentedClient(&mockHttpClient{mockResponse: buildMockResponse(401, "")})
+
+		_, err := client.GetLegislators(context.Background(), models.LegislatorsRequest{Id: "TX"})
+		test.AssertErrorExists(err, t)
+
+		span := spanRecorder.Ended()[0]
+		for _, kv := range span.Attributes() {
+			if strings.Contains(kv.Value.Emit(), telemetryTestAPIKey) {
+				t.Errorf("Attribute %s contains the API key", kv.Key)
+			}
+		}
+		if strings.Contains(span.Status().Description, telemetryTestAPIKey) {
+			t.Error("Span status contains the API key")
+		}
+	})
+	t.Run("Redacts the API key from transport errors that include the URL", func(t *testing.T) {
+		spanRecorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+		failingClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("Get %q: dial tcp: connection refused", req.URL.String())
+		})
+		client := newOpenSecretsClient(telemetryTestAPIKey, failingClient, []Option{WithTracerProvider(tracerProvider)})
+
+		_, err := client.GetLegislators(context.Background(), models.LegislatorsRequest{Id: "TX"})
+		test.AssertErrorExists(err, t)
+
+		span := spanRecorder.Ended()[0]
+		if strings.Contains(span.Status().Description, telemetryTestAPIKey) {
+			t.Errorf("Span status contains the API key: %s", span.Status().Description)
+		}
+		attributes := attributeMap(span.Attributes())
+		test.AssertStringMatches(attributes[ErrorTypeAttributeKey].AsString(), ErrorTypeTransport, t)
+	})
+}
+
+func attributeMap(attributes []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	toReturn := map[attribute.Key]attribute.Value{}
+	for _, kv := range attributes {
+		toReturn[kv.Key] = kv.Value
+	}
+	return toReturn
+}
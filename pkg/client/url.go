@@ -9,6 +9,25 @@ import (
 
 const baseUrl string = "http://www.opensecrets.org/api/"
 
+/*
+Sends requests to the provided base URL instead of http://www.opensecrets.org/api/, e.g. a mirror of the API or a local
+server in tests. Method parameters are appended to it as a query string (e.g. "?method=getLegislators&..."), so pass the
+URL up to where the query starts, such as "https://mirror.example.com/api/".
+*/
+func WithBaseURL(baseURL string) Option {
+	return func(o *openSecretsClient) {
+		o.baseURL = baseURL
+	}
+}
+
+// Moves a URL built on baseUrl to the client's base URL, if it has one. URLs already moved are returned as is.
+func (o *openSecretsClient) rebase(url string) string {
+	if o.baseURL == "" || !strings.HasPrefix(url, baseUrl) {
+		return url
+	}
+	return o.baseURL + strings.TrimPrefix(url, baseUrl)
+}
+
 func buildLegislatorsURL(request models.LegislatorsRequest, apiKey string) string {
 	return baseUrl + "?method=getLegislators&output=json&apikey=" + apiKey + "&id=" + request.Id
 }
@@ -1,6 +1,10 @@
 package client
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/KiaFarhang/opensecrets/internal/test"
@@ -172,3 +176,23 @@ func TestBuildIndependentExpendituresURL(t *testing.T) {
 		test.AssertStringMatches(url, expectedUrl, t)
 	})
 }
+
+func TestWithBaseURL(t *testing.T) {
+	t.Run("Sends requests to the provided base URL", func(t *testing.T) {
+		var requested string
+		httpClient := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			requested = req.URL.String()
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"response":{"legislator":[]}}`))}, nil
+		})
+		openSecretsClient := NewOpenSecretsClientWithHttpClient("hunter2", httpClient, WithBaseURL("https://mirror.example.com/api/"))
+
+		_, err := openSecretsClient.GetLegislators(context.Background(), models.LegislatorsRequest{Id: "TX"})
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(requested, "https://mirror.example.com/api/?method=getLegislators&output=json&apikey=hunter2&id=TX", t)
+	})
+	t.Run("Leaves URLs alone without a base URL", func(t *testing.T) {
+		o := &openSecretsClient{}
+		url := buildLegislatorsURL(models.LegislatorsRequest{Id: "TX"}, apiKey)
+		test.AssertStringMatches(o.rebase(url), url, t)
+	})
+}
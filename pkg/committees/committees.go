@@ -0,0 +1,170 @@
+/*
+Package committees provides a catalog of the congressional committee codes the OpenSecrets API accepts.
+
+The congCmteIndus method (OpenSecretsClient.GetCommitteeFundraisingDetails) identifies committees by a four-character
+CQ-format ID, like HARM for the House Armed Services Committee. This package embeds the House, Senate and joint committee
+codes along with their names, chambers and the range of Congresses they existed in, so you can look codes up and check
+a request before sending it. Committees are listed under their current (or final) names; earlier names are kept in
+FormerNames.
+*/
+package committees
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+//go:embed committees.json
+var catalogJSON []byte
+
+// The chamber a committee belongs to.
+type Chamber string
+
+const (
+	House  Chamber = "H"
+	Senate Chamber = "S"
+	Joint  Chamber = "J"
+)
+
+// The earliest Congress the catalog covers. Committees' existence before it isn't recorded.
+const FirstCatalogCongress int = 110
+
+const UnknownCommitteeErrorMessage string = "unknown congressional committee code"
+
+var ErrUnknownCommittee = errors.New(UnknownCommitteeErrorMessage)
+
+// A congressional committee in the catalog.
+type Committee struct {
+	Code          string  `json:"code"` // CQ-format committee ID (e.g. HARM)
+	Name          string  `json:"name"`
+	Chamber       Chamber `json:"chamber"`
+	FirstCongress int     `json:"first_congress"` // First Congress the committee existed in (or the first covered by the catalog)
+	LastCongress  int     `json:"last_congress"`  // Last Congress the committee existed in; 0 if it still exists
+
+	FormerNames []FormerName `json:"former_names,omitempty"`
+}
+
+// A name a committee went by before it was renamed.
+type FormerName struct {
+	Name          string `json:"name"`
+	FirstCongress int    `json:"first_congress"`
+	LastCongress  int    `json:"last_congress"`
+}
+
+// Returns the name the committee went by in the provided Congress (0 for the most recent Congress).
+func (c Committee) NameIn(congress int) string {
+	for _, former := range c.FormerNames {
+		if congress >= former.FirstCongress && congress <= former.LastCongress {
+			return former.Name
+		}
+	}
+	return c.Name
+}
+
+// Reports whether the committee existed in the provided Congress. A congress of 0 refers to the most recent Congress,
+// matching the default of FundraisingByCongressionalCommitteeRequest.CongressNumber.
+func (c Committee) ExistedIn(congress int) bool {
+	if congress == 0 {
+		return c.Current()
+	}
+	return congress >= c.FirstCongress && (c.LastCongress == 0 || congress <= c.LastCongress)
+}
+
+// Reports whether the committee still exists.
+func (c Committee) Current() bool {
+	return c.LastCongress == 0
+}
+
+var catalog = mustLoadCatalog(catalogJSON)
+
+func mustLoadCatalog(jsonBytes []byte) []Committee {
+	var committees []Committee
+	err := json.Unmarshal(jsonBytes, &committees)
+	if err != nil {
+		panic("unable to load embedded committee catalog: " + err.Error())
+	}
+	sort.Slice(committees, func(i, j int) bool { return committees[i].Code < committees[j].Code })
+	return committees
+}
+
+// Returns every committee in the catalog, sorted by code.
+func All() []Committee {
+	toReturn := make([]Committee, len(catalog))
+	copy(toReturn, catalog)
+	return toReturn
+}
+
+// Finds a committee by its CQ-format code. The lookup is case-insensitive.
+func Lookup(code string) (Committee, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	index := sort.Search(len(catalog), func(i int) bool { return catalog[i].Code >= code })
+	if index < len(catalog) && catalog[index].Code == code {
+		return catalog[index], true
+	}
+	return Committee{}, false
+}
+
+// Returns the committees belonging to the provided chamber.
+func ByChamber(chamber Chamber) []Committee {
+	return filter(func(c Committee) bool { return c.Chamber == chamber })
+}
+
+// Returns the committees that existed in the provided Congress (0 for the most recent Congress).
+func InCongress(congress int) []Committee {
+	return filter(func(c Committee) bool { return c.ExistedIn(congress) })
+}
+
+// Returns the committees whose current or former name contains the provided text, ignoring case.
+func SearchByName(name string) []Committee {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return filter(func(c Committee) bool {
+		if strings.Contains(strings.ToLower(c.Name), name) {
+			return true
+		}
+		for _, former := range c.FormerNames {
+			if strings.Contains(strings.ToLower(former.Name), name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Checks that the request's committee code is in the catalog and that the committee existed in the requested
+// Congress. Congresses before FirstCatalogCongress aren't checked, since the catalog doesn't cover them. It doesn't
+// check the request's required fields; the client does that when you make the call.
+func ValidateRequest(request models.FundraisingByCongressionalCommitteeRequest) error {
+	committee, found := Lookup(request.Committee)
+	if !found {
+		return fmt.Errorf("%w: %q", ErrUnknownCommittee, request.Committee)
+	}
+
+	if request.CongressNumber != 0 && request.CongressNumber < FirstCatalogCongress {
+		return nil
+	}
+
+	if !committee.ExistedIn(request.CongressNumber) {
+		if request.CongressNumber == 0 {
+			return fmt.Errorf("committee %s no longer exists; pass the CongressNumber it existed in", committee.Code)
+		}
+		return fmt.Errorf("committee %s did not exist in Congress %d", committee.Code, request.CongressNumber)
+	}
+
+	return nil
+}
+
+func filter(keep func(Committee) bool) []Committee {
+	var toReturn []Committee
+	for _, committee := range catalog {
+		if keep(committee) {
+			toReturn = append(toReturn, committee)
+		}
+	}
+	return toReturn
+}
@@ -0,0 +1,119 @@
+package committees
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func TestLookup(t *testing.T) {
+	t.Run("Finds a committee by code", func(t *testing.T) {
+		committee, found := Lookup("HARM")
+		if !found {
+			t.Fatal("Wanted to find HARM but didn't")
+		}
+		test.AssertStringMatches(committee.Name, "Armed Services", t)
+		test.AssertStringMatches(string(committee.Chamber), string(House), t)
+	})
+	t.Run("Ignores case and surrounding whitespace", func(t *testing.T) {
+		committee, found := Lookup(" sfin ")
+		if !found {
+			t.Fatal("Wanted to find SFIN but didn't")
+		}
+		test.AssertStringMatches(committee.Code, "SFIN", t)
+	})
+	t.Run("Returns false for an unknown code", func(t *testing.T) {
+		_, found := Lookup("ZZZZ")
+		if found {
+			t.Error("Wanted no committee for ZZZZ but found one")
+		}
+	})
+}
+
+func TestByChamber(t *testing.T) {
+	t.Run("Only returns committees from the requested chamber", func(t *testing.T) {
+		joint := ByChamber(Joint)
+		if len(joint) == 0 {
+			t.Fatal("Wanted joint committees but got none")
+		}
+		for _, committee := range joint {
+			test.AssertStringMatches(string(committee.Chamber), string(Joint), t)
+		}
+	})
+}
+
+func TestInCongress(t *testing.T) {
+	t.Run("Excludes committees that didn't exist in the Congress", func(t *testing.T) {
+		for _, committee := range InCongress(112) {
+			if committee.Code == "HCLI" {
+				t.Error("Wanted HCLI excluded from the 112th Congress")
+			}
+		}
+	})
+}
+
+func TestSearchByName(t *testing.T) {
+	t.Run("Matches partial names regardless of case", func(t *testing.T) {
+		results := SearchByName("armed")
+		test.AssertSliceLength(len(results), 2, t)
+	})
+	t.Run("Matches former names", func(t *testing.T) {
+		results := SearchByName("education & labor")
+		test.AssertSliceLength(len(results), 1, t)
+		test.AssertStringMatches(results[0].Code, "HEDU", t)
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	t.Run("Starts no earlier than FirstCatalogCongress", func(t *testing.T) {
+		for _, committee := range All() {
+			if committee.FirstCongress < FirstCatalogCongress {
+				t.Errorf("Wanted %s to start no earlier than the %dth Congress but got %d", committee.Code, FirstCatalogCongress, committee.FirstCongress)
+			}
+		}
+	})
+}
+
+func TestNameIn(t *testing.T) {
+	committee, _ := Lookup("HEDU")
+	t.Run("Returns the name the committee went by in that Congress", func(t *testing.T) {
+		test.AssertStringMatches(committee.NameIn(111), "Education & Labor", t)
+		test.AssertStringMatches(committee.NameIn(117), "Education & Labor", t)
+	})
+	t.Run("Returns the current name otherwise", func(t *testing.T) {
+		test.AssertStringMatches(committee.NameIn(112), "Education & the Workforce", t)
+		test.AssertStringMatches(committee.NameIn(0), "Education & the Workforce", t)
+	})
+}
+
+func TestValidateRequest(t *testing.T) {
+	t.Run("Accepts a current committee with no Congress number", func(t *testing.T) {
+		err := ValidateRequest(models.FundraisingByCongressionalCommitteeRequest{Committee: "HARM", Industry: "F10"})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Accepts a committee in a Congress it existed in", func(t *testing.T) {
+		err := ValidateRequest(models.FundraisingByCongressionalCommitteeRequest{Committee: "HCLI", Industry: "F10", CongressNumber: 116})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Returns an error for an unknown committee", func(t *testing.T) {
+		err := ValidateRequest(models.FundraisingByCongressionalCommitteeRequest{Committee: "ZZZZ", Industry: "F10"})
+		test.AssertErrorExists(err, t)
+		if !errors.Is(err, ErrUnknownCommittee) {
+			t.Errorf("Wanted ErrUnknownCommittee but got %s", err.Error())
+		}
+	})
+	t.Run("Returns an error for a Congress the committee didn't exist in", func(t *testing.T) {
+		err := ValidateRequest(models.FundraisingByCongressionalCommitteeRequest{Committee: "HCLI", Industry: "F10", CongressNumber: 112})
+		test.AssertErrorMessage(err, "committee HCLI did not exist in Congress 112", t)
+	})
+	t.Run("Doesn't check Congresses before the catalog's coverage", func(t *testing.T) {
+		err := ValidateRequest(models.FundraisingByCongressionalCommitteeRequest{Committee: "HARM", Industry: "F10", CongressNumber: 105})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Returns an error for a defunct committee with no Congress number", func(t *testing.T) {
+		err := ValidateRequest(models.FundraisingByCongressionalCommitteeRequest{Committee: "HBEN", Industry: "F10"})
+		test.AssertErrorExists(err, t)
+	})
+}
@@ -0,0 +1,258 @@
+/*
+Package crosswalk maps between the identifiers different sources use for the same member of Congress: CRP's candidate
+ID (CID), the FEC candidate ID, the Congressional Bioguide ID and the VoteSmart ID.
+
+Every candidate method in the client takes a CID, but other datasets usually key members by Bioguide or FEC ID.
+Legislator carries all four, so a Crosswalk built from legislators (or a roster) can turn any of them into the others,
+and its request helpers build client requests from whichever one you have:
+
+	r, err := roster.GetAllLegislators(ctx, openSecretsClient, client.BatchOptions{})
+	if err != nil {
+		return err
+	}
+	walk := crosswalk.FromRoster(r)
+
+	request, err := walk.CandidateSummaryRequest("P000197", 2022) // Nancy Pelosi's Bioguide ID
+	if err != nil {
+		return err
+	}
+	summary, err := openSecretsClient.GetCandidateSummary(ctx, request)
+*/
+package crosswalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/roster"
+)
+
+// A kind of identifier for a member of Congress.
+type IDType string
+
+const (
+	CID       IDType = "cid"       // CRP candidate ID, e.g. N00007360
+	FEC       IDType = "fec"       // FEC candidate ID, e.g. H8CA05035
+	Bioguide  IDType = "bioguide"  // Congressional Bioguide ID, e.g. P000197
+	VoteSmart IDType = "votesmart" // VoteSmart ID, e.g. 26732
+)
+
+// Every IDType, in the order they're checked by Crosswalk.Identify.
+var IDTypes = []IDType{CID, FEC, Bioguide, VoteSmart}
+
+// Every identifier known for one member. Any but Cid may be empty.
+type IDs struct {
+	Cid         string `json:"cid"`
+	FECCandId   string `json:"fec"`
+	BioguideId  string `json:"bioguide"`
+	VoteSmartId string `json:"votesmart"`
+}
+
+// Returns the member's identifier of the provided type, or "" if it isn't known.
+func (i IDs) Get(idType IDType) string {
+	switch idType {
+	case CID:
+		return i.Cid
+	case FEC:
+		return i.FECCandId
+	case Bioguide:
+		return i.BioguideId
+	case VoteSmart:
+		return i.VoteSmartId
+	default:
+		return ""
+	}
+}
+
+// Returned when an identifier isn't in the crosswalk.
+type UnknownIDError struct {
+	ID   string
+	Type IDType // Empty if the identifier was looked up as any type
+}
+
+func (u *UnknownIDError) Error() string {
+	if u.Type == "" {
+		return fmt.Sprintf("no member with identifier %q in the crosswalk", u.ID)
+	}
+	return fmt.Sprintf("no member with %s ID %q in the crosswalk", u.Type, u.ID)
+}
+
+// Identifiers for a set of members, indexed by each IDType. A Crosswalk is read-only once built and is safe for
+// concurrent use.
+type Crosswalk struct {
+	Entries []IDs // Sorted by CID
+
+	indexes map[IDType]map[string]int
+}
+
+// Builds a crosswalk from the provided entries, keeping the first entry for each CID and the first CID for each other
+// identifier. Entries without a CID are skipped.
+func New(entries []IDs) *Crosswalk {
+	seen := map[string]bool{}
+	var deduped []IDs
+	for _, entry := range entries {
+		entry = IDs{
+			Cid:         normalizeID(entry.Cid),
+			FECCandId:   normalizeID(entry.FECCandId),
+			BioguideId:  normalizeID(entry.BioguideId),
+			VoteSmartId: normalizeID(entry.VoteSmartId),
+		}
+		if entry.Cid == "" || seen[entry.Cid] {
+			continue
+		}
+		seen[entry.Cid] = true
+		deduped = append(deduped, entry)
+	}
+	sort.SliceStable(deduped, func(i, j int) bool { return deduped[i].Cid < deduped[j].Cid })
+
+	crosswalk := &Crosswalk{Entries: deduped, indexes: map[IDType]map[string]int{}}
+	for _, idType := range IDTypes {
+		crosswalk.indexes[idType] = map[string]int{}
+	}
+	for i, entry := range deduped {
+		for _, idType := range IDTypes {
+			id := entry.Get(idType)
+			if _, found := crosswalk.indexes[idType][id]; id != "" && !found {
+				crosswalk.indexes[idType][id] = i
+			}
+		}
+	}
+	return crosswalk
+}
+
+// Builds a crosswalk from the identifiers on the provided legislators.
+func FromLegislators(legislators []models.Legislator) *Crosswalk {
+	entries := make([]IDs, len(legislators))
+	for i, legislator := range legislators {
+		entries[i] = IDs{
+			Cid:         legislator.Cid,
+			FECCandId:   legislator.FECCandId,
+			BioguideId:  legislator.BioguideId,
+			VoteSmartId: legislator.VoteSmartId,
+		}
+	}
+	return New(entries)
+}
+
+// Builds a crosswalk from every member of the roster.
+func FromRoster(r *roster.Roster) *Crosswalk {
+	legislators := make([]models.Legislator, len(r.Members))
+	for i, member := range r.Members {
+		legislators[i] = member.Legislator
+	}
+	return FromLegislators(legislators)
+}
+
+// Returns the identifiers of the member with the provided identifier of the provided type, ignoring case and
+// surrounding whitespace, or false if there's no such member.
+func (c *Crosswalk) Lookup(idType IDType, id string) (IDs, bool) {
+	index, found := c.indexes[idType][normalizeID(id)]
+	if !found {
+		return IDs{}, false
+	}
+	return c.Entries[index], true
+}
+
+// Returns the identifiers of the member with the provided identifier of any type, and which type it was, or false if
+// there's no such member.
+func (c *Crosswalk) Identify(id string) (IDs, IDType, bool) {
+	for _, idType := range IDTypes {
+		if entry, found := c.Lookup(idType, id); found {
+			return entry, idType, true
+		}
+	}
+	return IDs{}, "", false
+}
+
+// Returns the identifier of type to for the member with identifier id of type from, or an *UnknownIDError if there's
+// no such member or the crosswalk doesn't know their identifier of type to.
+func (c *Crosswalk) Convert(id string, from IDType, to IDType) (string, error) {
+	entry, found := c.Lookup(from, id)
+	if !found || entry.Get(to) == "" {
+		return "", &UnknownIDError{ID: id, Type: from}
+	}
+	return entry.Get(to), nil
+}
+
+// Returns the CID of the member with the provided identifier of any type, or an *UnknownIDError if there's no such
+// member.
+func (c *Crosswalk) Cid(id string) (string, error) {
+	entry, _, found := c.Identify(id)
+	if !found {
+		return "", &UnknownIDError{ID: id}
+	}
+	return entry.Cid, nil
+}
+
+// Returns the number of members in the crosswalk.
+func (c *Crosswalk) Len() int {
+	return len(c.Entries)
+}
+
+// Builds a LegislatorsRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) LegislatorsRequest(id string) (models.LegislatorsRequest, error) {
+	cid, err := c.Cid(id)
+	return models.LegislatorsRequest{Id: cid}, err
+}
+
+// Builds a MemberPFDRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) MemberPFDRequest(id string, year int) (models.MemberPFDRequest, error) {
+	cid, err := c.Cid(id)
+	return models.MemberPFDRequest{Cid: cid, Year: year}, err
+}
+
+// Builds a CandidateSummaryRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) CandidateSummaryRequest(id string, cycle int) (models.CandidateSummaryRequest, error) {
+	cid, err := c.Cid(id)
+	return models.CandidateSummaryRequest{Cid: cid, Cycle: cycle}, err
+}
+
+// Builds a CandidateContributorsRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) CandidateContributorsRequest(id string, cycle int) (models.CandidateContributorsRequest, error) {
+	cid, err := c.Cid(id)
+	return models.CandidateContributorsRequest{Cid: cid, Cycle: cycle}, err
+}
+
+// Builds a CandidateIndustriesRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) CandidateIndustriesRequest(id string, cycle int) (models.CandidateIndustriesRequest, error) {
+	cid, err := c.Cid(id)
+	return models.CandidateIndustriesRequest{Cid: cid, Cycle: cycle}, err
+}
+
+// Builds a CandidateIndustryDetailsRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) CandidateIndustryDetailsRequest(id string, industry string, cycle int) (models.CandidateIndustryDetailsRequest, error) {
+	cid, err := c.Cid(id)
+	return models.CandidateIndustryDetailsRequest{Cid: cid, Ind: industry, Cycle: cycle}, err
+}
+
+// Builds a CandidateTopSectorsRequest for the member with the provided identifier of any type.
+func (c *Crosswalk) CandidateTopSectorsRequest(id string, cycle int) (models.CandidateTopSectorsRequest, error) {
+	cid, err := c.Cid(id)
+	return models.CandidateTopSectorsRequest{Cid: cid, Cycle: cycle}, err
+}
+
+type savedCrosswalk struct {
+	Entries []IDs `json:"entries"`
+}
+
+// Writes the crosswalk to the provided writer as JSON.
+func (c *Crosswalk) Save(writer io.Writer) error {
+	return json.NewEncoder(writer).Encode(savedCrosswalk{Entries: c.Entries})
+}
+
+// Reads a crosswalk written by Crosswalk.Save.
+func Load(reader io.Reader) (*Crosswalk, error) {
+	var saved savedCrosswalk
+	if err := json.NewDecoder(reader).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return New(saved.Entries), nil
+}
+
+func normalizeID(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
+}
@@ -0,0 +1,139 @@
+package crosswalk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/roster"
+)
+
+var legislators = []models.Legislator{
+	{Cid: "N00007360", FECCandId: "H8CA05035", BioguideId: "P000197", VoteSmartId: "26732"},
+	{Cid: "N00033085", FECCandId: "S2TX00312", BioguideId: "C001098", VoteSmartId: "135705"},
+	{Cid: "N00001093", BioguideId: "S000148"},
+}
+
+func TestLookup(t *testing.T) {
+	crosswalk := FromLegislators(legislators)
+
+	t.Run("Looks up members by every identifier type", func(t *testing.T) {
+		for idType, id := range map[IDType]string{CID: "N00007360", FEC: "H8CA05035", Bioguide: "P000197", VoteSmart: "26732"} {
+			entry, found := crosswalk.Lookup(idType, id)
+			if !found {
+				t.Fatalf("Wanted to find %s ID %s", idType, id)
+			}
+			test.AssertStringMatches(entry.Cid, "N00007360", t)
+		}
+	})
+	t.Run("Ignores case and whitespace", func(t *testing.T) {
+		entry, found := crosswalk.Lookup(Bioguide, " c001098 ")
+		if !found {
+			t.Fatal("Wanted to find c001098")
+		}
+		test.AssertStringMatches(entry.FECCandId, "S2TX00312", t)
+	})
+	t.Run("Returns false for unknown identifiers", func(t *testing.T) {
+		if _, found := crosswalk.Lookup(Bioguide, "N00007360"); found {
+			t.Error("Wanted a CID not to be found as a Bioguide ID")
+		}
+	})
+	t.Run("Identifies an identifier of any type", func(t *testing.T) {
+		entry, idType, found := crosswalk.Identify("S000148")
+		if !found {
+			t.Fatal("Wanted to find S000148")
+		}
+		test.AssertStringMatches(string(idType), string(Bioguide), t)
+		test.AssertStringMatches(entry.Cid, "N00001093", t)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	crosswalk := FromLegislators(legislators)
+
+	t.Run("Converts between identifier types", func(t *testing.T) {
+		fec, err := crosswalk.Convert("P000197", Bioguide, FEC)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(fec, "H8CA05035", t)
+	})
+	t.Run("Returns an UnknownIDError for a missing target identifier", func(t *testing.T) {
+		_, err := crosswalk.Convert("S000148", Bioguide, VoteSmart)
+		var unknownErr *UnknownIDError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("Wanted an UnknownIDError but got %v", err)
+		}
+		test.AssertStringMatches(string(unknownErr.Type), string(Bioguide), t)
+	})
+	t.Run("Returns the CID for any identifier", func(t *testing.T) {
+		cid, err := crosswalk.Cid("135705")
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(cid, "N00033085", t)
+
+		_, err = crosswalk.Cid("X999")
+		test.AssertErrorExists(err, t)
+	})
+}
+
+func TestRequestHelpers(t *testing.T) {
+	crosswalk := FromLegislators(legislators)
+
+	t.Run("Fills in the CID", func(t *testing.T) {
+		request, err := crosswalk.CandidateSummaryRequest("P000197", 2022)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(request.Cid, "N00007360", t)
+		test.AssertIntMatches(request.Cycle, 2022, t)
+
+		details, err := crosswalk.CandidateIndustryDetailsRequest("H8CA05035", "K02", 0)
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(details.Cid, "N00007360", t)
+		test.AssertStringMatches(details.Ind, "K02", t)
+	})
+	t.Run("Returns an error for unknown identifiers", func(t *testing.T) {
+		_, err := crosswalk.CandidateContributorsRequest("nobody", 2022)
+		var unknownErr *UnknownIDError
+		if !errors.As(err, &unknownErr) {
+			t.Errorf("Wanted an UnknownIDError but got %v", err)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("Keeps the first entry for each CID and skips entries without one", func(t *testing.T) {
+		crosswalk := New([]IDs{
+			{Cid: "N00007360", BioguideId: "P000197"},
+			{Cid: "n00007360", BioguideId: "X000000"},
+			{BioguideId: "C001098"},
+		})
+		test.AssertIntMatches(crosswalk.Len(), 1, t)
+		test.AssertStringMatches(crosswalk.Entries[0].BioguideId, "P000197", t)
+	})
+	t.Run("Builds from a roster", func(t *testing.T) {
+		r := roster.New([]roster.Member{{Legislator: legislators[0], State: "CA"}}, time.Now())
+		crosswalk := FromRoster(r)
+		_, found := crosswalk.Lookup(VoteSmart, "26732")
+		if !found {
+			t.Error("Wanted to find a roster member's VoteSmart ID")
+		}
+	})
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Run("Round-trips a crosswalk", func(t *testing.T) {
+		var buffer bytes.Buffer
+		test.AssertNoError(FromLegislators(legislators).Save(&buffer), t)
+
+		loaded, err := Load(&buffer)
+		test.AssertNoError(err, t)
+		test.AssertIntMatches(loaded.Len(), 3, t)
+		cid, err := loaded.Cid("C001098")
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(cid, "N00033085", t)
+	})
+	t.Run("Returns an error for malformed input", func(t *testing.T) {
+		_, err := Load(bytes.NewBufferString("not json"))
+		test.AssertErrorExists(err, t)
+	})
+}
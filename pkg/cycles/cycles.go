@@ -0,0 +1,127 @@
+/*
+Package cycles converts between election cycles, Congress numbers and calendar dates.
+
+Most OpenSecrets methods take a Cycle (the even year a two-year election cycle ends in, e.g. 2022), while
+congCmteIndus takes a CongressNumber (e.g. 117). The two line up: the 117th Congress sat from January 2021 to January
+2023, the same span as the 2022 cycle. Use the Period type to build a request from whichever form you have:
+
+	period := cycles.FromCongress(117)
+	request := models.CandidateSummaryRequest{Cid: "N00007360", Cycle: period.Cycle()}
+
+Keep in mind candidate data for senators covers six years (three cycles) ending in the cycle requested; SenateCycles
+returns the cycles those numbers span.
+*/
+package cycles
+
+import "time"
+
+// The first year of the 1st Congress's election cycle, used to convert between cycles and Congress numbers.
+const firstCycleBase int = 1788
+
+// Returns the election cycle containing the provided year: the year itself if it's even, otherwise the following year.
+func CycleForYear(year int) int {
+	if year%2 != 0 {
+		return year + 1
+	}
+	return year
+}
+
+// Returns the election cycle containing the provided time.
+func CycleForDate(t time.Time) int {
+	return CycleForYear(t.Year())
+}
+
+// Returns the current election cycle.
+func Current() int {
+	return CycleForDate(time.Now())
+}
+
+// Returns the Congress that sat during the provided cycle (e.g. 117 for 2022). Odd years are treated as part of the
+// cycle that ends the following year.
+func CongressForCycle(cycle int) int {
+	return (CycleForYear(cycle) - firstCycleBase) / 2
+}
+
+// Returns the election cycle the provided Congress sat during (e.g. 2022 for the 117th Congress).
+func CycleForCongress(congress int) int {
+	return firstCycleBase + 2*congress
+}
+
+// Returns the Congress sitting at the provided time.
+func CongressForDate(t time.Time) int {
+	congress := (t.Year() - 1787) / 2
+	start, _ := CongressDates(congress)
+	if t.Before(start) {
+		congress--
+	}
+	return congress
+}
+
+// Returns the Congress sitting now.
+func CurrentCongress() int {
+	return CongressForDate(time.Now())
+}
+
+// Returns the first and last days of the provided cycle: January 1 of the preceding odd year through December 31 of
+// the cycle year, in UTC.
+func CycleDates(cycle int) (time.Time, time.Time) {
+	cycle = CycleForYear(cycle)
+	start := time.Date(cycle-1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(cycle, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return start, end
+}
+
+// Returns when the provided Congress convened and when its successor convened, in UTC. Since the 74th Congress
+// (1935) terms have begun on January 3 of odd years; before that they began on March 4.
+func CongressDates(congress int) (time.Time, time.Time) {
+	return congressStart(congress), congressStart(congress + 1)
+}
+
+func congressStart(congress int) time.Time {
+	year := 1787 + 2*congress
+	if congress < 74 {
+		return time.Date(year, time.March, 4, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, time.January, 3, 0, 0, 0, 0, time.UTC)
+}
+
+// Returns the three cycles a senator's six-year numbers cover when the provided cycle is requested, oldest first.
+func SenateCycles(cycle int) []int {
+	cycle = CycleForYear(cycle)
+	return []int{cycle - 4, cycle - 2, cycle}
+}
+
+// A two-year period identified by either its election cycle or its Congress number.
+type Period struct {
+	cycle int
+}
+
+// Construct a Period from an election cycle (odd years are rounded up to the cycle they belong to).
+func FromCycle(cycle int) Period {
+	return Period{cycle: CycleForYear(cycle)}
+}
+
+// Construct a Period from a Congress number.
+func FromCongress(congress int) Period {
+	return Period{cycle: CycleForCongress(congress)}
+}
+
+// Construct the Period containing the provided time.
+func FromDate(t time.Time) Period {
+	return Period{cycle: CycleForDate(t)}
+}
+
+// Returns the period's election cycle, for use as a request's Cycle.
+func (p Period) Cycle() int {
+	return p.cycle
+}
+
+// Returns the period's Congress number, for use as a request's CongressNumber.
+func (p Period) CongressNumber() int {
+	return CongressForCycle(p.cycle)
+}
+
+// Returns the first and last days of the period's cycle.
+func (p Period) Dates() (time.Time, time.Time) {
+	return CycleDates(p.cycle)
+}
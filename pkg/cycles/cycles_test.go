@@ -0,0 +1,77 @@
+package cycles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+func TestCycleForYear(t *testing.T) {
+	t.Run("Returns even years unchanged", func(t *testing.T) {
+		test.AssertIntMatches(CycleForYear(2022), 2022, t)
+	})
+	t.Run("Rounds odd years up", func(t *testing.T) {
+		test.AssertIntMatches(CycleForYear(2021), 2022, t)
+	})
+}
+
+func TestCongressConversions(t *testing.T) {
+	t.Run("Converts a cycle to a Congress number", func(t *testing.T) {
+		test.AssertIntMatches(CongressForCycle(2022), 117, t)
+		test.AssertIntMatches(CongressForCycle(2021), 117, t)
+		test.AssertIntMatches(CongressForCycle(1990), 101, t)
+	})
+	t.Run("Converts a Congress number to a cycle", func(t *testing.T) {
+		test.AssertIntMatches(CycleForCongress(117), 2022, t)
+		test.AssertIntMatches(CycleForCongress(116), 2020, t)
+	})
+}
+
+func TestCongressForDate(t *testing.T) {
+	t.Run("Returns the Congress sitting on a date", func(t *testing.T) {
+		test.AssertIntMatches(CongressForDate(time.Date(2022, time.July, 4, 0, 0, 0, 0, time.UTC)), 117, t)
+	})
+	t.Run("Returns the outgoing Congress before January 3", func(t *testing.T) {
+		test.AssertIntMatches(CongressForDate(time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)), 117, t)
+		test.AssertIntMatches(CongressForDate(time.Date(2023, time.January, 3, 0, 0, 0, 0, time.UTC)), 118, t)
+	})
+}
+
+func TestCongressDates(t *testing.T) {
+	t.Run("Returns January 3 start dates for modern Congresses", func(t *testing.T) {
+		start, end := CongressDates(117)
+		test.AssertStringMatches(start.Format("2006-01-02"), "2021-01-03", t)
+		test.AssertStringMatches(end.Format("2006-01-02"), "2023-01-03", t)
+	})
+	t.Run("Returns March 4 start dates before the 74th Congress", func(t *testing.T) {
+		start, _ := CongressDates(1)
+		test.AssertStringMatches(start.Format("2006-01-02"), "1789-03-04", t)
+	})
+}
+
+func TestCycleDates(t *testing.T) {
+	t.Run("Spans the odd and even years of the cycle", func(t *testing.T) {
+		start, end := CycleDates(2022)
+		test.AssertStringMatches(start.Format("2006-01-02"), "2021-01-01", t)
+		test.AssertStringMatches(end.Format("2006-01-02"), "2022-12-31", t)
+	})
+}
+
+func TestSenateCycles(t *testing.T) {
+	t.Run("Returns the three cycles of a six-year term", func(t *testing.T) {
+		senateCycles := SenateCycles(2022)
+		test.AssertSliceLength(len(senateCycles), 3, t)
+		test.AssertIntMatches(senateCycles[0], 2018, t)
+		test.AssertIntMatches(senateCycles[2], 2022, t)
+	})
+}
+
+func TestPeriod(t *testing.T) {
+	t.Run("Builds the same period from either form", func(t *testing.T) {
+		fromCongress := FromCongress(117)
+		fromCycle := FromCycle(2022)
+		test.AssertIntMatches(fromCongress.Cycle(), fromCycle.Cycle(), t)
+		test.AssertIntMatches(fromCycle.CongressNumber(), 117, t)
+	})
+}
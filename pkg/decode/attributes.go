@@ -0,0 +1,177 @@
+package decode
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// An Option changes how a response is parsed.
+type Option func(*config)
+
+type config struct {
+	strict bool
+}
+
+// Strict makes parsing fail with a *SchemaDriftError when a response has attributes the models don't know about, or is
+// missing attributes they expect. Without it, unknown attributes are kept in each model's Extra map and missing ones are
+// left as zero values.
+func Strict() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// Unknown and missing attributes found on one kind of object in a response (e.g. every legislator in a getLegislators
+// response).
+type Drift struct {
+	Object  string   // The object the attributes belong to (e.g. legislator, summary)
+	Unknown []string // Attributes in the response with no matching model field
+	Missing []string // Model fields with no matching attribute in the response
+}
+
+// Returned by the Parse and Decode functions in strict mode when a response doesn't match the models. The parsed value is still
+// returned alongside it.
+type SchemaDriftError struct {
+	Drifts []Drift
+}
+
+func (s *SchemaDriftError) Error() string {
+	var descriptions []string
+	for _, drift := range s.Drifts {
+		var parts []string
+		if len(drift.Unknown) > 0 {
+			parts = append(parts, fmt.Sprintf("unknown attributes %v", drift.Unknown))
+		}
+		if len(drift.Missing) > 0 {
+			parts = append(parts, fmt.Sprintf("missing attributes %v", drift.Missing))
+		}
+		descriptions = append(descriptions, drift.Object+" has "+strings.Join(parts, " and "))
+	}
+	return "OpenSecrets response schema changed: " + strings.Join(descriptions, "; ")
+}
+
+// Wraps an @attributes object, decoding it into a model while keeping the raw attributes so they can be compared to
+// the model's fields.
+type attributes[T any] struct {
+	value T
+	raw   map[string]json.RawMessage
+}
+
+func (a *attributes[T]) UnmarshalJSON(jsonBytes []byte) error {
+	err := json.Unmarshal(jsonBytes, &a.value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBytes, &a.raw)
+}
+
+type driftCollector struct {
+	config  config
+	drifts  []Drift
+	byIndex map[string]int
+}
+
+func newDriftCollector(options []Option) *driftCollector {
+	collector := &driftCollector{byIndex: map[string]int{}}
+	for _, option := range options {
+		option(&collector.config)
+	}
+	return collector
+}
+
+// Returns the model decoded from the attributes with its Extra map populated, recording any drift against the object
+// name provided.
+func inspect[T any](collector *driftCollector, object string, wrapped attributes[T]) T {
+	model := wrapped.value
+	known := knownAttributes(reflect.TypeOf(model))
+
+	extra := map[string]string{}
+	var unknown, missing []string
+	for key, value := range wrapped.raw {
+		if !known[key] {
+			unknown = append(unknown, key)
+			extra[key] = rawString(value)
+		}
+	}
+	for key := range known {
+		if _, found := wrapped.raw[key]; !found {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(extra) > 0 {
+		reflect.ValueOf(&model).Elem().FieldByName("Extra").Set(reflect.ValueOf(extra))
+	}
+
+	collector.record(object, unknown, missing)
+	return model
+}
+
+func (d *driftCollector) record(object string, unknown, missing []string) {
+	if len(unknown) == 0 && len(missing) == 0 {
+		return
+	}
+	index, found := d.byIndex[object]
+	if !found {
+		d.drifts = append(d.drifts, Drift{Object: object})
+		index = len(d.drifts) - 1
+		d.byIndex[object] = index
+	}
+	drift := &d.drifts[index]
+	drift.Unknown = mergeSorted(drift.Unknown, unknown)
+	drift.Missing = mergeSorted(drift.Missing, missing)
+}
+
+// Returns a *SchemaDriftError if strict mode is on and any drift was recorded, otherwise nil.
+func (d *driftCollector) err() error {
+	if !d.config.strict || len(d.drifts) == 0 {
+		return nil
+	}
+	return &SchemaDriftError{Drifts: d.drifts}
+}
+
+var knownAttributesCache sync.Map
+
+// Returns the set of attribute names a model type has JSON-tagged fields for.
+func knownAttributes(modelType reflect.Type) map[string]bool {
+	if cached, found := knownAttributesCache.Load(modelType); found {
+		return cached.(map[string]bool)
+	}
+	known := map[string]bool{}
+	for i := 0; i < modelType.NumField(); i++ {
+		tag := modelType.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	knownAttributesCache.Store(modelType, known)
+	return known
+}
+
+func rawString(value json.RawMessage) string {
+	var asString string
+	if json.Unmarshal(value, &asString) == nil {
+		return asString
+	}
+	return string(value)
+}
+
+func mergeSorted(existing, toAdd []string) []string {
+	seen := map[string]bool{}
+	for _, value := range existing {
+		seen[value] = true
+	}
+	for _, value := range toAdd {
+		if !seen[value] {
+			seen[value] = true
+			existing = append(existing, value)
+		}
+	}
+	sort.Strings(existing)
+	return existing
+}
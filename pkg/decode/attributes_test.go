@@ -0,0 +1,71 @@
+package decode
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+func TestExtraAttributes(t *testing.T) {
+	t.Run("Captures attributes the model has no field for", func(t *testing.T) {
+		json := []byte(`{"response": {"legislator": [{"@attributes": {"cid": "N00007360", "new_field": "hello", "count": 3}}]}}`)
+		legislators, err := ParseLegislatorsJSON(json)
+		test.AssertNoError(err, t)
+
+		extra := legislators[0].Extra
+		test.AssertStringMatches(extra["new_field"], "hello", t)
+		test.AssertStringMatches(extra["count"], "3", t)
+		if _, found := extra["cid"]; found {
+			t.Error("Wanted known attributes left out of Extra")
+		}
+	})
+	t.Run("Leaves Extra nil when every attribute is known", func(t *testing.T) {
+		json := []byte(`{"response": {"legislator": [{"@attributes": {"cid": "N00007360"}}]}}`)
+		legislators, err := ParseLegislatorsJSON(json)
+		test.AssertNoError(err, t)
+		if legislators[0].Extra != nil {
+			t.Errorf("Wanted nil Extra but got %v", legislators[0].Extra)
+		}
+	})
+}
+
+func TestStrict(t *testing.T) {
+	t.Run("Returns no error when the response matches the models", func(t *testing.T) {
+		json, err := ioutil.ReadFile("../../internal/mocks/mockCandidateContributorsResponse.json")
+		test.AssertNoError(err, t)
+
+		_, err = ParseCandidateContributorsJSON(json, Strict())
+		test.AssertNoError(err, t)
+	})
+	t.Run("Reports unknown and missing attributes per object", func(t *testing.T) {
+		json := []byte(`{"response": {"organization": [
+			{"@attributes": {"orgid": "D000000125", "org_name": "Renamed"}},
+			{"@attributes": {"orgid": "D000000126", "org_name": "Renamed", "extra": "x"}}
+		]}}`)
+		results, err := ParseOrganizationSearchJSON(json, Strict())
+
+		var driftError *SchemaDriftError
+		if !errors.As(err, &driftError) {
+			t.Fatalf("Wanted a *SchemaDriftError but got %v", err)
+		}
+		test.AssertSliceLength(len(driftError.Drifts), 1, t)
+
+		drift := driftError.Drifts[0]
+		test.AssertStringMatches(drift.Object, "organization", t)
+		test.AssertSliceLength(len(drift.Unknown), 2, t)
+		test.AssertStringMatches(drift.Unknown[0], "extra", t)
+		test.AssertStringMatches(drift.Unknown[1], "org_name", t)
+		test.AssertSliceLength(len(drift.Missing), 1, t)
+		test.AssertStringMatches(drift.Missing[0], "orgname", t)
+
+		test.AssertSliceLength(len(results), 2, t)
+		test.AssertStringMatches(results[0].Extra["org_name"], "Renamed", t)
+	})
+	t.Run("Ignores drift when strict mode is off", func(t *testing.T) {
+		json := []byte(`{"response": {"organization": [{"@attributes": {"orgid": "D000000125", "org_name": "Renamed"}}]}}`)
+		_, err := ParseOrganizationSearchJSON(json)
+		test.AssertNoError(err, t)
+	})
+}
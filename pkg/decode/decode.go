@@ -0,0 +1,423 @@
+/*
+Package decode turns OpenSecrets API JSON responses into types from the models package.
+
+The client uses it to parse every response, but you can also use it directly, e.g. to decode OpenSecrets responses you
+archived to disk without going through HTTP. Each API method has a Parse*JSON function that takes a byte slice and a
+Decode* function that reads from an io.Reader:
+
+	file, err := os.Open("legislators_TX.json")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	legislators, err := decode.DecodeLegislators(file)
+*/
+package decode
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+const UnableToParseErrorMessage string = "unable to parse OpenSecrets response body"
+
+// Returned by the Decode* functions when what was read isn't a response they can parse.
+var ErrUnableToParse = errors.New(UnableToParseErrorMessage)
+
+/*
+Returned by the Decode* functions when reading from the reader fails, as opposed to what was read being unparseable.
+(e.g. the connection dropped mid-response, or the client's maximum response size was exceeded)
+*/
+type ReadError struct {
+	Err error
+}
+
+func (r *ReadError) Error() string {
+	return "unable to read OpenSecrets response body: " + r.Err.Error()
+}
+
+func (r *ReadError) Unwrap() error {
+	return r.Err
+}
+
+// Remembers the first error, other than io.EOF, its reader returned.
+type errorCapturingReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (e *errorCapturingReader) Read(p []byte) (int, error) {
+	n, err := e.reader.Read(p)
+	if err != nil && err != io.EOF && e.err == nil {
+		e.err = err
+	}
+	return n, err
+}
+
+// Decodes a single JSON document from the reader into the provided response wrapper, failing if the reader holds
+// anything after it. Failures to read from the reader are returned as a *ReadError.
+func decodeResponse(reader io.Reader, responseWrapper interface{}) error {
+	capturingReader := &errorCapturingReader{reader: reader}
+	decoder := json.NewDecoder(capturingReader)
+	err := decoder.Decode(responseWrapper)
+	more := err == nil && decoder.More()
+	if capturingReader.err != nil {
+		return &ReadError{Err: capturingReader.err}
+	}
+	if err != nil {
+		return err
+	}
+	if more {
+		return errors.New("unexpected data after OpenSecrets response body")
+	}
+	return nil
+}
+
+// Returns read errors as they are, and hides any other decoding error behind ErrUnableToParse.
+func decodeError(err error) error {
+	var readError *ReadError
+	if errors.As(err, &readError) {
+		return readError
+	}
+	return ErrUnableToParse
+}
+
+// Decodes a getLegislators response read from the provided reader.
+func DecodeLegislators(reader io.Reader, options ...Option) ([]models.Legislator, error) {
+
+	type legislatorResponse struct {
+		Response struct {
+			Legislator []struct {
+				Attributes attributes[models.Legislator] `json:"@attributes"`
+			} `json:"legislator"`
+		} `json:"response"`
+	}
+
+	var responseWrapper = legislatorResponse{}
+	err := decodeResponse(reader, &responseWrapper)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+
+	var toReturn []models.Legislator
+	for _, legislatorWrapper := range responseWrapper.Response.Legislator {
+		toReturn = append(toReturn, inspect(drift, "legislator", legislatorWrapper.Attributes))
+	}
+
+	return toReturn, drift.err()
+}
+
+// Decodes a memPFDProfile response read from the provided reader.
+func DecodeMemberPFD(reader io.Reader, options ...Option) (models.MemberProfile, error) {
+
+	type memberPFDResponse struct {
+		Response struct {
+			Wrapper struct {
+				Profile      attributes[models.MemberProfile] `json:"@attributes"`
+				AssetWrapper struct {
+					Assets []struct {
+						Asset attributes[models.Asset] `json:"@attributes"`
+					} `json:"asset"`
+				} `json:"assets"`
+				TransactionWrapper struct {
+					Transactions []struct {
+						Transaction attributes[models.Transaction] `json:"@attributes"`
+					} `json:"transaction"`
+				} `json:"transactions"`
+				PositionWrapper struct {
+					Positions []struct {
+						Position attributes[models.Position] `json:"@attributes"`
+					} `json:"position"`
+				} `json:"positions"`
+			} `json:"member_profile"`
+		} `json:"response"`
+	}
+
+	var memberProfile models.MemberProfile
+	var responseWrapper = memberPFDResponse{}
+	err := decodeResponse(reader, &responseWrapper)
+	if err != nil {
+		return memberProfile, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+
+	memberProfile = inspect(drift, "member_profile", responseWrapper.Response.Wrapper.Profile)
+
+	var memberAssets []models.Asset
+	assetWrappers := responseWrapper.Response.Wrapper.AssetWrapper.Assets
+	for _, assetWrapper := range assetWrappers {
+		memberAssets = append(memberAssets, inspect(drift, "asset", assetWrapper.Asset))
+	}
+	memberProfile.Assets = memberAssets
+
+	var memberTransactions []models.Transaction
+	transactionWrappers := responseWrapper.Response.Wrapper.TransactionWrapper.Transactions
+	for _, transactionWrapper := range transactionWrappers {
+		memberTransactions = append(memberTransactions, inspect(drift, "transaction", transactionWrapper.Transaction))
+	}
+	memberProfile.Transactions = memberTransactions
+
+	var memberPositions []models.Position
+	positionWrappers := responseWrapper.Response.Wrapper.PositionWrapper.Positions
+	for _, positionWrapper := range positionWrappers {
+		memberPositions = append(memberPositions, inspect(drift, "position", positionWrapper.Position))
+	}
+	memberProfile.Positions = memberPositions
+
+	return memberProfile, drift.err()
+}
+
+// Decodes a candSummary response read from the provided reader.
+func DecodeCandidateSummary(reader io.Reader, options ...Option) (models.CandidateSummary, error) {
+	type candidateSummaryResponse struct {
+		Response struct {
+			Summary struct {
+				Attributes attributes[models.CandidateSummary] `json:"@attributes"`
+			} `json:"summary"`
+		} `json:"response"`
+	}
+
+	var responseWrapper candidateSummaryResponse
+	err := decodeResponse(reader, &responseWrapper)
+	if err != nil {
+		return models.CandidateSummary{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+	summary := inspect(drift, "summary", responseWrapper.Response.Summary.Attributes)
+
+	return summary, drift.err()
+}
+
+// Decodes a candContrib response read from the provided reader.
+func DecodeCandidateContributors(reader io.Reader, options ...Option) (models.CandidateContributorSummary, error) {
+
+	type candidateContributorResponse struct {
+		Response struct {
+			Contributors struct {
+				Attributes   attributes[models.CandidateContributorSummary] `json:"@attributes"`
+				Contributors []struct {
+					Attributes attributes[models.CandidateContributor] `json:"@attributes"`
+				} `json:"contributor"`
+			} `json:"contributors"`
+		} `json:"response"`
+	}
+
+	var responseWrapper candidateContributorResponse
+	err := decodeResponse(reader, &responseWrapper)
+	if err != nil {
+		return models.CandidateContributorSummary{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+
+	var contributors []models.CandidateContributor
+
+	for _, contributor := range responseWrapper.Response.Contributors.Contributors {
+		contributors = append(contributors, inspect(drift, "contributor", contributor.Attributes))
+	}
+
+	summary := inspect(drift, "contributors", responseWrapper.Response.Contributors.Attributes)
+	summary.Contributors = contributors
+
+	return summary, drift.err()
+}
+
+// Decodes a candIndustry response read from the provided reader.
+func DecodeCandidateIndustries(reader io.Reader, options ...Option) (models.CandidateIndustriesSummary, error) {
+	type candidateIndustriesResponse struct {
+		Response struct {
+			Industries struct {
+				Attributes attributes[models.CandidateIndustriesSummary] `json:"@attributes"`
+				Industry   []struct {
+					Attributes attributes[models.Industry] `json:"@attributes"`
+				} `json:"industry"`
+			} `json:"industries"`
+		} `json:"response"`
+	}
+
+	var responseWrapper candidateIndustriesResponse
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return models.CandidateIndustriesSummary{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+	summary := inspect(drift, "industries", responseWrapper.Response.Industries.Attributes)
+
+	for _, industry := range responseWrapper.Response.Industries.Industry {
+		summary.Industries = append(summary.Industries, inspect(drift, "industry", industry.Attributes))
+	}
+
+	return summary, drift.err()
+}
+
+// Decodes a candIndByInd response read from the provided reader.
+func DecodeCandidateIndustryDetails(reader io.Reader, options ...Option) (models.CandidateIndustryDetails, error) {
+	type candidateIndustryDetailsResponse struct {
+		Response struct {
+			Wrapper struct {
+				Attributes attributes[models.CandidateIndustryDetails] `json:"@attributes"`
+			} `json:"candIndus"`
+		} `json:"response"`
+	}
+
+	var responseWrapper candidateIndustryDetailsResponse
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return models.CandidateIndustryDetails{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+	details := inspect(drift, "candIndus", responseWrapper.Response.Wrapper.Attributes)
+
+	return details, drift.err()
+}
+
+// Decodes a candSector response read from the provided reader.
+func DecodeCandidateTopSectors(reader io.Reader, options ...Option) (models.CandidateTopSectorDetails, error) {
+	type candidateSectorsResponse struct {
+		Response struct {
+			Wrapper struct {
+				CandidateDetails attributes[models.CandidateTopSectorDetails] `json:"@attributes"`
+				SectorList       []struct {
+					SectorAttributes attributes[models.Sector] `json:"@attributes"`
+				} `json:"sector"`
+			} `json:"sectors"`
+		} `json:"response"`
+	}
+
+	var responseWrapper candidateSectorsResponse
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return models.CandidateTopSectorDetails{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+	details := inspect(drift, "sectors", responseWrapper.Response.Wrapper.CandidateDetails)
+
+	for _, sector := range responseWrapper.Response.Wrapper.SectorList {
+		details.Sectors = append(details.Sectors, inspect(drift, "sector", sector.SectorAttributes))
+	}
+
+	return details, drift.err()
+}
+
+// Decodes a congCmteIndus response read from the provided reader.
+func DecodeFundraisingByCommittee(reader io.Reader, options ...Option) (models.CommitteeFundraisingDetails, error) {
+	type fundraisingByCommitteeResponse struct {
+		Response struct {
+			Wrapper struct {
+				CommitteeDetails attributes[models.CommitteeFundraisingDetails] `json:"@attributes"`
+				MemberList       []struct {
+					Member attributes[models.CommitteeMember] `json:"@attributes"`
+				} `json:"member"`
+			} `json:"committee"`
+		} `json:"response"`
+	}
+
+	var responseWrapper fundraisingByCommitteeResponse
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return models.CommitteeFundraisingDetails{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+	details := inspect(drift, "committee", responseWrapper.Response.Wrapper.CommitteeDetails)
+
+	for _, member := range responseWrapper.Response.Wrapper.MemberList {
+		details.Members = append(details.Members, inspect(drift, "member", member.Member))
+	}
+
+	return details, drift.err()
+}
+
+// Decodes a getOrgs response read from the provided reader.
+func DecodeOrganizationSearch(reader io.Reader, options ...Option) ([]models.OrganizationSearchResult, error) {
+	type organizationSearchResponse struct {
+		Response struct {
+			Wrapper []struct {
+				Attributes attributes[models.OrganizationSearchResult] `json:"@attributes"`
+			} `json:"organization"`
+		} `json:"response"`
+	}
+
+	var responseWrapper organizationSearchResponse
+	var toReturn []models.OrganizationSearchResult
+
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return toReturn, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+
+	for _, result := range responseWrapper.Response.Wrapper {
+		toReturn = append(toReturn, inspect(drift, "organization", result.Attributes))
+	}
+
+	return toReturn, drift.err()
+}
+
+// Decodes an orgSummary response read from the provided reader.
+func DecodeOrganizationSummary(reader io.Reader, options ...Option) (models.OrganizationSummary, error) {
+	type organizationSummaryResponse struct {
+		Response struct {
+			Wrapper struct {
+				Attributes attributes[models.OrganizationSummary] `json:"@attributes"`
+			} `json:"organization"`
+		} `json:"response"`
+	}
+
+	var responseWrapper organizationSummaryResponse
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return models.OrganizationSummary{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+	summary := inspect(drift, "organization", responseWrapper.Response.Wrapper.Attributes)
+
+	return summary, drift.err()
+}
+
+// Decodes an independentExpend response read from the provided reader.
+func DecodeIndependentExpenditures(reader io.Reader, options ...Option) ([]models.IndependentExpenditure, error) {
+	type independentExpendituresResponse struct {
+		Response struct {
+			List []struct {
+				Expenditure attributes[models.IndependentExpenditure] `json:"@attributes"`
+			} `json:"indexp"`
+		} `json:"response"`
+	}
+
+	var responseWrapper independentExpendituresResponse
+	err := decodeResponse(reader, &responseWrapper)
+
+	if err != nil {
+		return []models.IndependentExpenditure{}, decodeError(err)
+	}
+
+	drift := newDriftCollector(options)
+
+	var toReturn []models.IndependentExpenditure
+
+	for _, expenditure := range responseWrapper.Response.List {
+		toReturn = append(toReturn, inspect(drift, "indexp", expenditure.Expenditure))
+	}
+
+	return toReturn, drift.err()
+}
@@ -0,0 +1,57 @@
+package decode
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+func TestDecodeFromReader(t *testing.T) {
+	t.Run("Decodes an archived response straight from disk", func(t *testing.T) {
+		file, err := os.Open("../../internal/mocks/mockCandidateIndustriesResponse.json")
+		test.AssertNoError(err, t)
+		defer file.Close()
+
+		summary, err := DecodeCandidateIndustries(file)
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(summary.Industries), 10, t)
+	})
+	t.Run("Accepts the same options as the Parse functions", func(t *testing.T) {
+		reader := strings.NewReader(`{"response": {"summary": {"@attributes": {"cid": "N00007360", "brand_new": "1"}}}}`)
+		_, err := DecodeCandidateSummary(reader, Strict())
+		test.AssertErrorExists(err, t)
+	})
+	t.Run("Returns an error for invalid JSON", func(t *testing.T) {
+		_, err := DecodeLegislators(strings.NewReader(`GARBAGE`))
+		test.AssertErrorMessage(err, UnableToParseErrorMessage, t)
+	})
+	t.Run("Returns an error for data after the response body", func(t *testing.T) {
+		_, err := DecodeLegislators(strings.NewReader(`{"response": {}} {"response": {}}`))
+		test.AssertErrorMessage(err, UnableToParseErrorMessage, t)
+	})
+	t.Run("Returns a *ReadError when reading fails, wrapping the reader's error", func(t *testing.T) {
+		readFailure := errors.New("connection reset")
+		reader := io.MultiReader(strings.NewReader(`{"response": {"legislator": [`), &failingReader{err: readFailure})
+
+		_, err := DecodeLegislators(reader)
+		var readError *ReadError
+		if !errors.As(err, &readError) {
+			t.Fatalf("Wanted a *ReadError but got %v", err)
+		}
+		if !errors.Is(err, readFailure) {
+			t.Errorf("Wanted the error to wrap the reader's error but got %v", err)
+		}
+	})
+}
+
+type failingReader struct {
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	return 0, f.err
+}
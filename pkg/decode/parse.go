@@ -0,0 +1,62 @@
+package decode
+
+import (
+	"bytes"
+
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// Parses a getLegislators response body.
+func ParseLegislatorsJSON(jsonBytes []byte, options ...Option) ([]models.Legislator, error) {
+	return DecodeLegislators(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a memPFDProfile response body.
+func ParseMemberPFDJSON(jsonBytes []byte, options ...Option) (models.MemberProfile, error) {
+	return DecodeMemberPFD(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a candSummary response body.
+func ParseCandidateSummaryJSON(jsonBytes []byte, options ...Option) (models.CandidateSummary, error) {
+	return DecodeCandidateSummary(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a candContrib response body.
+func ParseCandidateContributorsJSON(jsonBytes []byte, options ...Option) (models.CandidateContributorSummary, error) {
+	return DecodeCandidateContributors(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a candIndustry response body.
+func ParseCandidateIndustriesJSON(jsonBytes []byte, options ...Option) (models.CandidateIndustriesSummary, error) {
+	return DecodeCandidateIndustries(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a candIndByInd response body.
+func ParseCandidateIndustryDetailsJSON(jsonBytes []byte, options ...Option) (models.CandidateIndustryDetails, error) {
+	return DecodeCandidateIndustryDetails(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a candSector response body.
+func ParseCandidateTopSectorsJSON(jsonBytes []byte, options ...Option) (models.CandidateTopSectorDetails, error) {
+	return DecodeCandidateTopSectors(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a congCmteIndus response body.
+func ParseFundraisingByCommitteeJSON(jsonBytes []byte, options ...Option) (models.CommitteeFundraisingDetails, error) {
+	return DecodeFundraisingByCommittee(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses a getOrgs response body.
+func ParseOrganizationSearchJSON(jsonBytes []byte, options ...Option) ([]models.OrganizationSearchResult, error) {
+	return DecodeOrganizationSearch(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses an orgSummary response body.
+func ParseOrganizationSummaryJSON(jsonBytes []byte, options ...Option) (models.OrganizationSummary, error) {
+	return DecodeOrganizationSummary(bytes.NewReader(jsonBytes), options...)
+}
+
+// Parses an independentExpend response body.
+func ParseIndependentExpendituresJSON(jsonBytes []byte, options ...Option) ([]models.IndependentExpenditure, error) {
+	return DecodeIndependentExpenditures(bytes.NewReader(jsonBytes), options...)
+}
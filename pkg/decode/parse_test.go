@@ -1,6 +1,7 @@
-package parse
+package decode
 
 import (
+	"errors"
 	"io/ioutil"
 	"testing"
 
@@ -23,12 +24,15 @@ func TestParseLegislatorsJSON(t *testing.T) {
 		json := []byte(`GARBAGE`)
 		_, err := ParseLegislatorsJSON(json)
 		test.AssertErrorMessage(err, UnableToParseErrorMessage, t)
+		if !errors.Is(err, ErrUnableToParse) {
+			t.Errorf("Wanted ErrUnableToParse but got %v", err)
+		}
 	})
 }
 
 func TestParseMemberPFDJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockPFDResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockPFDResponse.json")
 		test.AssertNoError(err, t)
 
 		member, err := ParseMemberPFDJSON(json)
@@ -71,7 +75,7 @@ func TestParseMemberPFDJSON(t *testing.T) {
 
 func TestParseCandidateSummaryJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockCandidateSummaryResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockCandidateSummaryResponse.json")
 		test.AssertNoError(err, t)
 
 		candidateSummary, err := ParseCandidateSummaryJSON(json)
@@ -95,7 +99,7 @@ func TestParseCandidateSummaryJSON(t *testing.T) {
 
 func TestParseCandidateContributorsJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockCandidateContributorsResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockCandidateContributorsResponse.json")
 		test.AssertNoError(err, t)
 
 		contributorSummary, err := ParseCandidateContributorsJSON(json)
@@ -128,7 +132,7 @@ func TestParseCandidateContributorsJSON(t *testing.T) {
 
 func TestParseCandidateIndustriesJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockCandidateIndustriesResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockCandidateIndustriesResponse.json")
 		test.AssertNoError(err, t)
 
 		industrySummary, err := ParseCandidateIndustriesJSON(json)
@@ -156,7 +160,7 @@ func TestParseCandidateIndustriesJSON(t *testing.T) {
 
 func TestParseCandidateIndustryDetailsJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockCandidateIndustryDetailsResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockCandidateIndustryDetailsResponse.json")
 		test.AssertNoError(err, t)
 
 		details, err := ParseCandidateIndustryDetailsJSON(json)
@@ -177,7 +181,7 @@ func TestParseCandidateIndustryDetailsJSON(t *testing.T) {
 
 func TestParseCandidateTopSectorsJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockCandidateTopSectorsResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockCandidateTopSectorsResponse.json")
 		test.AssertNoError(err, t)
 
 		details, err := ParseCandidateTopSectorsJSON(json)
@@ -205,7 +209,7 @@ func TestParseCandidateTopSectorsJSON(t *testing.T) {
 
 func TestParseFundraisingByCommitteeJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockFundraisingByCommitteeResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockFundraisingByCommitteeResponse.json")
 		test.AssertNoError(err, t)
 
 		details, err := ParseFundraisingByCommitteeJSON(json)
@@ -231,7 +235,7 @@ func TestParseFundraisingByCommitteeJSON(t *testing.T) {
 
 func TestParseOrganizationSearchJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockOrganizationSearchResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockOrganizationSearchResponse.json")
 		test.AssertNoError(err, t)
 
 		searchResults, err := ParseOrganizationSearchJSON(json)
@@ -253,7 +257,7 @@ func TestParseOrganizationSearchJSON(t *testing.T) {
 
 func TestParseOrganizationSummaryJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockOrganizationSummaryResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockOrganizationSummaryResponse.json")
 		test.AssertNoError(err, t)
 
 		summary, err := ParseOrganizationSummaryJSON(json)
@@ -271,7 +275,7 @@ func TestParseOrganizationSummaryJSON(t *testing.T) {
 
 func TestParseIndependentExpendituresJSON(t *testing.T) {
 	t.Run("Correctly parses valid JSON", func(t *testing.T) {
-		json, err := ioutil.ReadFile("../mocks/mockIndependentExpendituresResponse.json")
+		json, err := ioutil.ReadFile("../../internal/mocks/mockIndependentExpendituresResponse.json")
 		test.AssertNoError(err, t)
 
 		expenditures, err := ParseIndependentExpendituresJSON(json)
@@ -0,0 +1,342 @@
+/*
+Package diff compares two snapshots of the same candidate's or organization's data, fetched at different times, and
+reports what changed.
+
+Fetching a member's summary or top contributors again later tells you their new numbers, not what moved. The functions
+here compare an earlier snapshot with a later one and return a Diff of the totals that changed and the contributors or
+industries that were added, removed or changed, with amount deltas, ready for alerting on or writing to a changelog:
+
+	d, err := diff.CandidateContributors(yesterday, today)
+	if err != nil {
+		return err
+	}
+	for _, entry := range d.Filter(10000).Added() {
+		alert("%s is a new top contributor to %s", entry.Name, today.CandidateName)
+	}
+	fmt.Print(d)
+*/
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// Whether an entry in a list was added, removed or changed between snapshots.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"   // In the later snapshot but not the earlier one
+	Removed ChangeKind = "removed" // In the earlier snapshot but not the later one
+	Changed ChangeKind = "changed" // In both, with different amounts or a different position
+)
+
+// An amount that differs between snapshots.
+type FieldChange struct {
+	Field  string // The name of the struct field, e.g. CashOnHand
+	Before float64
+	After  float64
+	Delta  float64 // After minus Before
+}
+
+// Returns the change as a percentage of the earlier amount, or false if the earlier amount was 0.
+func (f FieldChange) Percent() (float64, bool) {
+	if f.Before == 0 {
+		return 0, false
+	}
+	return f.Delta / math.Abs(f.Before) * 100, true
+}
+
+/*
+A contributor or industry that was added, removed or changed. For a changed entry, Fields lists the amounts that
+changed; for an added entry it lists every amount with a Before of 0, and for a removed entry every amount with an After
+of 0. Ranks are 1-based positions in each snapshot's list, and 0 when the entry isn't in that snapshot.
+*/
+type EntryChange struct {
+	Kind       ChangeKind
+	Key        string // What the entry is matched by across snapshots: the organization name or industry code
+	Name       string
+	RankBefore int
+	RankAfter  int
+	Fields     []FieldChange
+}
+
+// The differences between two snapshots.
+type Diff struct {
+	Fields  []FieldChange // Top-level amounts that changed, like a summary's CashOnHand
+	Entries []EntryChange // Added and changed entries in the later snapshot's order, then removed entries
+}
+
+const MismatchedSnapshotsErrorMessage string = "snapshots to diff must be for the same candidate or organization and cycle"
+
+var ErrMismatchedSnapshots = errors.New(MismatchedSnapshotsErrorMessage)
+
+// Reports whether nothing changed.
+func (d Diff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.Entries) == 0
+}
+
+// Returns the added entries.
+func (d Diff) Added() []EntryChange {
+	return d.entries(Added)
+}
+
+// Returns the removed entries.
+func (d Diff) Removed() []EntryChange {
+	return d.entries(Removed)
+}
+
+// Returns the changed entries.
+func (d Diff) Changed() []EntryChange {
+	return d.entries(Changed)
+}
+
+/*
+Returns a copy of the diff without the amount changes smaller than minDelta (in either direction), and without changed
+entries left with no amount changes and no change in rank. Added and removed entries are always kept, so alerts can
+ignore small fluctuations without missing entries appearing or disappearing.
+*/
+func (d Diff) Filter(minDelta float64) Diff {
+	var filtered Diff
+	filtered.Fields = filterFields(d.Fields, minDelta)
+	for _, entry := range d.Entries {
+		if entry.Kind == Changed {
+			entry.Fields = filterFields(entry.Fields, minDelta)
+			if len(entry.Fields) == 0 && entry.RankBefore == entry.RankAfter {
+				continue
+			}
+		}
+		filtered.Entries = append(filtered.Entries, entry)
+	}
+	return filtered
+}
+
+// Formats the diff as a changelog, one change per line.
+func (d Diff) String() string {
+	var builder strings.Builder
+	for _, field := range d.Fields {
+		fmt.Fprintf(&builder, "%s\n", formatField(field))
+	}
+	for _, entry := range d.Entries {
+		switch entry.Kind {
+		case Added:
+			fmt.Fprintf(&builder, "+ %s (#%d)", entry.Name, entry.RankAfter)
+			for _, field := range entry.Fields {
+				fmt.Fprintf(&builder, ", %s %.2f", field.Field, field.After)
+			}
+		case Removed:
+			fmt.Fprintf(&builder, "- %s (was #%d)", entry.Name, entry.RankBefore)
+		case Changed:
+			if entry.RankBefore == entry.RankAfter {
+				fmt.Fprintf(&builder, "~ %s (#%d)", entry.Name, entry.RankAfter)
+			} else {
+				fmt.Fprintf(&builder, "~ %s (#%d -> #%d)", entry.Name, entry.RankBefore, entry.RankAfter)
+			}
+			for _, field := range entry.Fields {
+				fmt.Fprintf(&builder, ", %s", formatField(field))
+			}
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// Compares two snapshots of a candidate's summary. Returns ErrMismatchedSnapshots if they're for different candidates
+// or cycles.
+func CandidateSummary(before models.CandidateSummary, after models.CandidateSummary) (Diff, error) {
+	if before.Cid != after.Cid || before.Cycle != after.Cycle {
+		return Diff{}, mismatched(before.Cid, before.Cycle, after.Cid, after.Cycle)
+	}
+	return Diff{Fields: compareFields(
+		namedAmount{"Total", before.Total, after.Total},
+		namedAmount{"Spent", before.Spent, after.Spent},
+		namedAmount{"CashOnHand", before.CashOnHand, after.CashOnHand},
+		namedAmount{"Debt", before.Debt, after.Debt},
+	)}, nil
+}
+
+// Compares two snapshots of a candidate's top contributors, matching contributors by organization name. Returns
+// ErrMismatchedSnapshots if they're for different candidates or cycles.
+func CandidateContributors(before models.CandidateContributorSummary, after models.CandidateContributorSummary) (Diff, error) {
+	if before.Cid != after.Cid || before.Cycle != after.Cycle {
+		return Diff{}, mismatched(before.Cid, before.Cycle, after.Cid, after.Cycle)
+	}
+	return Diff{Entries: compareEntries(contributorEntries(before.Contributors), contributorEntries(after.Contributors))}, nil
+}
+
+// Compares two snapshots of a candidate's top industries, matching industries by industry code. Returns
+// ErrMismatchedSnapshots if they're for different candidates or cycles.
+func CandidateIndustries(before models.CandidateIndustriesSummary, after models.CandidateIndustriesSummary) (Diff, error) {
+	if before.Cid != after.Cid || before.Cycle != after.Cycle {
+		return Diff{}, mismatched(before.Cid, before.Cycle, after.Cid, after.Cycle)
+	}
+	return Diff{Entries: compareEntries(industryEntries(before.Industries), industryEntries(after.Industries))}, nil
+}
+
+// Compares two snapshots of an organization's summary. Returns ErrMismatchedSnapshots if they're for different
+// organizations or cycles.
+func OrganizationSummary(before models.OrganizationSummary, after models.OrganizationSummary) (Diff, error) {
+	if before.Id != after.Id || before.Cycle != after.Cycle {
+		return Diff{}, fmt.Errorf("%w: %s %s and %s %s", ErrMismatchedSnapshots, before.Id, before.Cycle, after.Id, after.Cycle)
+	}
+	return Diff{Fields: compareFields(
+		namedAmount{"TotalContributions", before.TotalContributions, after.TotalContributions},
+		namedAmount{"PacContributions", before.PacContributions, after.PacContributions},
+		namedAmount{"IndividualContributions", before.IndividualContributions, after.IndividualContributions},
+		namedAmount{"Soft", before.Soft, after.Soft},
+		namedAmount{"TotalFrom527Organizations", before.TotalFrom527Organizations, after.TotalFrom527Organizations},
+		namedAmount{"TotalToDemocrats", before.TotalToDemocrats, after.TotalToDemocrats},
+		namedAmount{"TotalToRepublicans", before.TotalToRepublicans, after.TotalToRepublicans},
+		namedAmount{"TotalSpentLobyying", before.TotalSpentLobyying, after.TotalSpentLobyying},
+		namedAmount{"TotalSpentOnIndependentExpenditures", before.TotalSpentOnIndependentExpenditures, after.TotalSpentOnIndependentExpenditures},
+		namedAmount{"MembersInvested", float64(before.MembersInvested), float64(after.MembersInvested)},
+		namedAmount{"TotalGaveToPacs", before.TotalGaveToPacs, after.TotalGaveToPacs},
+		namedAmount{"TotalGaveToPartyCommittees", before.TotalGaveToPartyCommittees, after.TotalGaveToPartyCommittees},
+		namedAmount{"TotalGaveTo527Organizations", before.TotalGaveTo527Organizations, after.TotalGaveTo527Organizations},
+		namedAmount{"TotalGaveToCandidates", before.TotalGaveToCandidates, after.TotalGaveToCandidates},
+	)}, nil
+}
+
+// A field's amount in each snapshot.
+type namedAmount struct {
+	field  string
+	before float64
+	after  float64
+}
+
+// The amounts compared for each contributor or industry.
+var entryFields = []string{"Total", "Pacs", "Individuals"}
+
+// A contributor or industry, reduced to what's compared.
+type entry struct {
+	key     string
+	name    string
+	amounts []float64 // In entryFields order
+}
+
+func contributorEntries(contributors []models.CandidateContributor) []entry {
+	entries := make([]entry, len(contributors))
+	for i, contributor := range contributors {
+		entries[i] = entry{
+			key:     contributor.OrganizationName,
+			name:    contributor.OrganizationName,
+			amounts: []float64{contributor.Total, contributor.Pacs, contributor.Individuals},
+		}
+	}
+	return entries
+}
+
+func industryEntries(industries []models.Industry) []entry {
+	entries := make([]entry, len(industries))
+	for i, industry := range industries {
+		entries[i] = entry{
+			key:     industry.IndustryCode,
+			name:    industry.IndustryName,
+			amounts: []float64{industry.Total, industry.Pacs, industry.Individuals},
+		}
+	}
+	return entries
+}
+
+// Matches the entries of two snapshots by key, keeping the first entry for a key that appears more than once.
+func compareEntries(before []entry, after []entry) []EntryChange {
+	beforeIndexes := map[string]int{}
+	for i, entry := range before {
+		if _, found := beforeIndexes[entry.key]; !found {
+			beforeIndexes[entry.key] = i
+		}
+	}
+
+	var changes []EntryChange
+	matched := map[string]bool{}
+	for i, afterEntry := range after {
+		if matched[afterEntry.key] {
+			continue
+		}
+		matched[afterEntry.key] = true
+
+		beforeIndex, found := beforeIndexes[afterEntry.key]
+		if !found {
+			fields := pairAmounts(make([]float64, len(entryFields)), afterEntry.amounts)
+			changes = append(changes, EntryChange{Kind: Added, Key: afterEntry.key, Name: afterEntry.name, RankAfter: i + 1, Fields: allFields(fields)})
+			continue
+		}
+
+		fields := compareFields(pairAmounts(before[beforeIndex].amounts, afterEntry.amounts)...)
+		if len(fields) > 0 || beforeIndex != i {
+			changes = append(changes, EntryChange{Kind: Changed, Key: afterEntry.key, Name: afterEntry.name, RankBefore: beforeIndex + 1, RankAfter: i + 1, Fields: fields})
+		}
+	}
+
+	for i, beforeEntry := range before {
+		if matched[beforeEntry.key] {
+			continue
+		}
+		matched[beforeEntry.key] = true
+		fields := pairAmounts(beforeEntry.amounts, make([]float64, len(entryFields)))
+		changes = append(changes, EntryChange{Kind: Removed, Key: beforeEntry.key, Name: beforeEntry.name, RankBefore: i + 1, Fields: allFields(fields)})
+	}
+
+	return changes
+}
+
+// Names an entry's amounts in each snapshot with entryFields.
+func pairAmounts(before []float64, after []float64) []namedAmount {
+	amounts := make([]namedAmount, len(entryFields))
+	for i, field := range entryFields {
+		amounts[i] = namedAmount{field: field, before: before[i], after: after[i]}
+	}
+	return amounts
+}
+
+// Returns a FieldChange for each amount that differs.
+func compareFields(amounts ...namedAmount) []FieldChange {
+	var changes []FieldChange
+	for _, amount := range amounts {
+		if amount.before != amount.after {
+			changes = append(changes, FieldChange{Field: amount.field, Before: amount.before, After: amount.after, Delta: amount.after - amount.before})
+		}
+	}
+	return changes
+}
+
+// Returns a FieldChange for every amount, even unchanged ones.
+func allFields(amounts []namedAmount) []FieldChange {
+	changes := make([]FieldChange, len(amounts))
+	for i, amount := range amounts {
+		changes[i] = FieldChange{Field: amount.field, Before: amount.before, After: amount.after, Delta: amount.after - amount.before}
+	}
+	return changes
+}
+
+func filterFields(fields []FieldChange, minDelta float64) []FieldChange {
+	var filtered []FieldChange
+	for _, field := range fields {
+		if math.Abs(field.Delta) >= minDelta {
+			filtered = append(filtered, field)
+		}
+	}
+	return filtered
+}
+
+func (d Diff) entries(kind ChangeKind) []EntryChange {
+	var toReturn []EntryChange
+	for _, entry := range d.Entries {
+		if entry.Kind == kind {
+			toReturn = append(toReturn, entry)
+		}
+	}
+	return toReturn
+}
+
+func formatField(field FieldChange) string {
+	return fmt.Sprintf("%s %.2f -> %.2f (%+.2f)", field.Field, field.Before, field.After, field.Delta)
+}
+
+func mismatched(beforeCid string, beforeCycle int, afterCid string, afterCycle int) error {
+	return fmt.Errorf("%w: %s %d and %s %d", ErrMismatchedSnapshots, beforeCid, beforeCycle, afterCid, afterCycle)
+}
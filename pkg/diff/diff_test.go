@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func contributors(cycle int, contributors ...models.CandidateContributor) models.CandidateContributorSummary {
+	return models.CandidateContributorSummary{Cid: "N00007360", Cycle: cycle, Contributors: contributors}
+}
+
+func TestCandidateSummary(t *testing.T) {
+	t.Run("Reports changed amounts with deltas", func(t *testing.T) {
+		before := models.CandidateSummary{Cid: "N00007360", Cycle: 2022, Total: 1000, CashOnHand: 500}
+		after := models.CandidateSummary{Cid: "N00007360", Cycle: 2022, Total: 1500, CashOnHand: 250}
+
+		d, err := CandidateSummary(before, after)
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(d.Fields), 2, t)
+		test.AssertStringMatches(d.Fields[1].Field, "CashOnHand", t)
+		if d.Fields[1].Delta != -250 {
+			t.Errorf("Wanted a delta of -250 but got %f", d.Fields[1].Delta)
+		}
+		if percent, ok := d.Fields[1].Percent(); !ok || percent != -50 {
+			t.Errorf("Wanted -50%% but got %f", percent)
+		}
+	})
+	t.Run("Returns an empty diff for identical snapshots", func(t *testing.T) {
+		summary := models.CandidateSummary{Cid: "N00007360", Cycle: 2022, Total: 1000}
+		d, err := CandidateSummary(summary, summary)
+		test.AssertNoError(err, t)
+		if !d.Empty() {
+			t.Errorf("Wanted an empty diff but got %v", d)
+		}
+	})
+	t.Run("Refuses to compare different candidates or cycles", func(t *testing.T) {
+		_, err := CandidateSummary(models.CandidateSummary{Cid: "N00007360", Cycle: 2022}, models.CandidateSummary{Cid: "N00007360", Cycle: 2020})
+		if !errors.Is(err, ErrMismatchedSnapshots) {
+			t.Errorf("Wanted ErrMismatchedSnapshots but got %v", err)
+		}
+	})
+}
+
+func TestCandidateContributors(t *testing.T) {
+	before := contributors(2022,
+		models.CandidateContributor{OrganizationName: "Alpha", Total: 300},
+		models.CandidateContributor{OrganizationName: "Bravo", Total: 200},
+		models.CandidateContributor{OrganizationName: "Charlie", Total: 100},
+	)
+	after := contributors(2022,
+		models.CandidateContributor{OrganizationName: "Bravo", Total: 400, Pacs: 50},
+		models.CandidateContributor{OrganizationName: "Alpha", Total: 300},
+		models.CandidateContributor{OrganizationName: "Delta", Total: 150},
+	)
+
+	t.Run("Reports added, removed and changed contributors", func(t *testing.T) {
+		d, err := CandidateContributors(before, after)
+		test.AssertNoError(err, t)
+
+		added := d.Added()
+		test.AssertSliceLength(len(added), 1, t)
+		test.AssertStringMatches(added[0].Name, "Delta", t)
+		test.AssertIntMatches(added[0].RankAfter, 3, t)
+
+		removed := d.Removed()
+		test.AssertSliceLength(len(removed), 1, t)
+		test.AssertStringMatches(removed[0].Name, "Charlie", t)
+		test.AssertIntMatches(removed[0].RankBefore, 3, t)
+
+		changed := d.Changed()
+		test.AssertSliceLength(len(changed), 2, t)
+		test.AssertStringMatches(changed[0].Name, "Bravo", t)
+		test.AssertSliceLength(len(changed[0].Fields), 2, t)
+		if changed[0].Fields[0].Delta != 200 {
+			t.Errorf("Wanted a Total delta of 200 but got %f", changed[0].Fields[0].Delta)
+		}
+		// Alpha only moved from #1 to #2
+		test.AssertIntMatches(changed[1].RankBefore, 1, t)
+		test.AssertIntMatches(changed[1].RankAfter, 2, t)
+		test.AssertSliceLength(len(changed[1].Fields), 0, t)
+	})
+	t.Run("Filters out small amount changes but keeps added and removed entries", func(t *testing.T) {
+		d, _ := CandidateContributors(before, after)
+		filtered := d.Filter(100)
+
+		test.AssertSliceLength(len(filtered.Added()), 1, t)
+		test.AssertSliceLength(len(filtered.Removed()), 1, t)
+		changed := filtered.Changed()
+		test.AssertSliceLength(len(changed), 2, t)
+		test.AssertSliceLength(len(changed[0].Fields), 1, t)
+	})
+	t.Run("Formats a changelog", func(t *testing.T) {
+		d, _ := CandidateContributors(before, after)
+		changelog := d.String()
+		for _, line := range []string{
+			"~ Bravo (#2 -> #1), Total 200.00 -> 400.00 (+200.00), Pacs 0.00 -> 50.00 (+50.00)",
+			"+ Delta (#3), Total 150.00",
+			"- Charlie (was #3)",
+		} {
+			if !strings.Contains(changelog, line) {
+				t.Errorf("Wanted the changelog to contain %q but got\n%s", line, changelog)
+			}
+		}
+	})
+	t.Run("Refuses to compare different cycles", func(t *testing.T) {
+		_, err := CandidateContributors(contributors(2020), contributors(2022))
+		if !errors.Is(err, ErrMismatchedSnapshots) {
+			t.Errorf("Wanted ErrMismatchedSnapshots but got %v", err)
+		}
+	})
+}
+
+func TestCandidateIndustries(t *testing.T) {
+	t.Run("Matches industries by code", func(t *testing.T) {
+		before := models.CandidateIndustriesSummary{Cid: "N00007360", Industries: []models.Industry{{IndustryCode: "K01", IndustryName: "Lawyers", Total: 100}}}
+		after := models.CandidateIndustriesSummary{Cid: "N00007360", Industries: []models.Industry{{IndustryCode: "K01", IndustryName: "Lawyers/Law Firms", Total: 120}}}
+
+		d, err := CandidateIndustries(before, after)
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(d.Entries), 1, t)
+		test.AssertStringMatches(string(d.Entries[0].Kind), string(Changed), t)
+		test.AssertStringMatches(d.Entries[0].Key, "K01", t)
+	})
+}
+
+func TestOrganizationSummary(t *testing.T) {
+	t.Run("Reports changed amounts", func(t *testing.T) {
+		before := models.OrganizationSummary{Id: "D000000085", Cycle: "2022", TotalSpentLobyying: 10, MembersInvested: 3}
+		after := models.OrganizationSummary{Id: "D000000085", Cycle: "2022", TotalSpentLobyying: 10, MembersInvested: 5}
+
+		d, err := OrganizationSummary(before, after)
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(d.Fields), 1, t)
+		test.AssertStringMatches(d.Fields[0].Field, "MembersInvested", t)
+	})
+	t.Run("Refuses to compare different organizations", func(t *testing.T) {
+		_, err := OrganizationSummary(models.OrganizationSummary{Id: "D000000085"}, models.OrganizationSummary{Id: "D000000086"})
+		if !errors.Is(err, ErrMismatchedSnapshots) {
+			t.Errorf("Wanted ErrMismatchedSnapshots but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+/*
+Package history fetches a candidate's fundraising across a range of election cycles and lines it up for charting.
+
+Each candidate method in the client returns a single cycle. GetCandidateHistory calls GetCandidateSummary,
+GetCandidateIndustries and GetCandidateTopSectorDetails for every cycle in a range, then exposes the results as series
+that share one set of cycles, so a member's totals, cash on hand, debt and top industries can be plotted over their
+career:
+
+	h, err := history.GetCandidateHistory(ctx, openSecretsClient, "N00007360", 2012, 2022, client.BatchOptions{})
+	if err != nil {
+		return err
+	}
+
+	totals := h.Totals()
+	for i, cycle := range totals.Cycles {
+		if totals.Present[i] {
+			fmt.Println(cycle, totals.Values[i])
+		}
+	}
+*/
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/KiaFarhang/opensecrets/internal/ids"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/cycles"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/validation"
+)
+
+// A part of a Point, filled in by one client call. It mirrors profile.Section for the three sections a history has, but
+// is its own type so history doesn't depend on the profile package and its six-call fan-out.
+type Section string
+
+const (
+	SummarySection    Section = "summary"    // GetCandidateSummary
+	IndustriesSection Section = "industries" // GetCandidateIndustries
+	SectorsSection    Section = "sectors"    // GetCandidateTopSectorDetails
+)
+
+// Every Section, in the order they appear on Point.
+var Sections = []Section{SummarySection, IndustriesSection, SectorsSection}
+
+// What the API returned for one cycle. Each section is nil if its call failed, in which case its error is in Errors;
+// a candidate who didn't run in a cycle typically has every section missing.
+type Point struct {
+	Cycle      int
+	Summary    *models.CandidateSummary
+	Industries *models.CandidateIndustriesSummary
+	Sectors    *models.CandidateTopSectorDetails
+	Errors     map[Section]error
+}
+
+// A candidate's fundraising across a range of cycles.
+type History struct {
+	Cid    string
+	Cycles []int   // Every cycle in the requested range, oldest first
+	Points []Point // Points[i] is for Cycles[i]
+}
+
+/*
+Values for each of a History's cycles. Values[i] is for Cycles[i], and is 0 with Present[i] false when there's no data
+for that cycle, so a chart can leave a gap rather than plot a misleading zero.
+*/
+type Series struct {
+	Cycles  []int
+	Values  []float64
+	Present []bool
+}
+
+// A Series for one industry or sector.
+type NamedSeries struct {
+	Code string // Industry code or sector ID
+	Name string
+	Series
+}
+
+const InvalidRangeErrorMessage string = "history range must be a CRP candidate ID and a from cycle no later than the to cycle, both between the earliest cycle OpenSecrets has data for and the current cycle"
+
+var ErrInvalidRange = errors.New(InvalidRangeErrorMessage)
+
+/*
+Fetches the candidate's summary, industries and sectors for every cycle from fromCycle to toCycle (inclusive; odd
+years are rounded up to their cycle). Both must be between validation.MinCycle and the current cycle.
+
+Every section of every cycle is its own call in a client.RunBatch, so options' concurrency and rate limits apply to
+each API call, and progress is reported per call (three per cycle).
+
+The returned error is only non-nil if the arguments are invalid or every call for every cycle failed; otherwise check
+each Point's Errors.
+*/
+func GetCandidateHistory(ctx context.Context, openSecretsClient client.OpenSecretsClient, cid string, fromCycle int, toCycle int, options client.BatchOptions) (History, error) {
+	fromCycle, toCycle = cycles.CycleForYear(fromCycle), cycles.CycleForYear(toCycle)
+	if !ids.IsCID(cid) || fromCycle > toCycle || fromCycle < validation.MinCycle || toCycle > cycles.Current() {
+		return History{}, fmt.Errorf("%w: %q, %d to %d (cycles must be between %d and %d)", ErrInvalidRange, cid, fromCycle, toCycle, validation.MinCycle, cycles.Current())
+	}
+
+	history := History{Cid: cid}
+	var calls []sectionCall
+	for cycle := fromCycle; cycle <= toCycle; cycle += 2 {
+		history.Cycles = append(history.Cycles, cycle)
+		history.Points = append(history.Points, Point{Cycle: cycle, Errors: map[Section]error{}})
+		for _, section := range Sections {
+			calls = append(calls, sectionCall{cycle: cycle, section: section})
+		}
+	}
+
+	results, _ := client.RunBatch(ctx, calls, func(ctx context.Context, call sectionCall) (Point, error) {
+		return fetchSection(ctx, openSecretsClient, cid, call)
+	}, options)
+
+	for i, result := range results {
+		point := &history.Points[i/len(Sections)]
+		if result.Err != nil {
+			// Includes calls the batch never started because ctx was done
+			point.Errors[result.Request.section] = result.Err
+			continue
+		}
+		if result.Value.Summary != nil {
+			point.Summary = result.Value.Summary
+		}
+		if result.Value.Industries != nil {
+			point.Industries = result.Value.Industries
+		}
+		if result.Value.Sectors != nil {
+			point.Sectors = result.Value.Sectors
+		}
+	}
+
+	var failures []error
+	anyData := false
+	for _, point := range history.Points {
+		if point.Summary != nil || point.Industries != nil || point.Sectors != nil {
+			anyData = true
+			continue
+		}
+		for _, section := range Sections {
+			failures = append(failures, fmt.Errorf("%d %s: %w", point.Cycle, section, point.Errors[section]))
+		}
+	}
+
+	if !anyData {
+		return history, errors.Join(failures...)
+	}
+	return history, nil
+}
+
+// One client call in a history: a section of one cycle.
+type sectionCall struct {
+	cycle   int
+	section Section
+}
+
+// Makes the client call for one section of one cycle, returning a Point with only that section filled in.
+func fetchSection(ctx context.Context, openSecretsClient client.OpenSecretsClient, cid string, call sectionCall) (Point, error) {
+	point := Point{Cycle: call.cycle}
+	switch call.section {
+	case SummarySection:
+		summary, err := openSecretsClient.GetCandidateSummary(ctx, models.CandidateSummaryRequest{Cid: cid, Cycle: call.cycle})
+		if err != nil {
+			return point, err
+		}
+		point.Summary = &summary
+	case IndustriesSection:
+		industries, err := openSecretsClient.GetCandidateIndustries(ctx, models.CandidateIndustriesRequest{Cid: cid, Cycle: call.cycle})
+		if err != nil {
+			return point, err
+		}
+		point.Industries = &industries
+	case SectorsSection:
+		sectors, err := openSecretsClient.GetCandidateTopSectorDetails(ctx, models.CandidateTopSectorsRequest{Cid: cid, Cycle: call.cycle})
+		if err != nil {
+			return point, err
+		}
+		point.Sectors = &sectors
+	}
+	return point, nil
+}
+
+// Returns total receipts for each cycle.
+func (h History) Totals() Series {
+	return h.summarySeries(func(summary *models.CandidateSummary) float64 { return summary.Total })
+}
+
+// Returns total expenditures for each cycle.
+func (h History) Spent() Series {
+	return h.summarySeries(func(summary *models.CandidateSummary) float64 { return summary.Spent })
+}
+
+// Returns cash on hand for each cycle.
+func (h History) CashOnHand() Series {
+	return h.summarySeries(func(summary *models.CandidateSummary) float64 { return summary.CashOnHand })
+}
+
+// Returns debt for each cycle.
+func (h History) Debt() Series {
+	return h.summarySeries(func(summary *models.CandidateSummary) float64 { return summary.Debt })
+}
+
+/*
+Returns a series of totals for every industry that was among the candidate's top industries in any cycle, largest
+overall first. An industry's value is missing for cycles it wasn't in the top industries (or industries couldn't be
+fetched).
+*/
+func (h History) Industries() []NamedSeries {
+	collector := newNamedSeriesCollector(h.Cycles)
+	for i, point := range h.Points {
+		if point.Industries != nil {
+			for _, industry := range point.Industries.Industries {
+				collector.add(i, industry.IndustryCode, industry.IndustryName, industry.Total)
+			}
+		}
+	}
+	return collector.sorted()
+}
+
+// Returns a series of totals for every sector the candidate received money from in any cycle, largest overall first.
+func (h History) Sectors() []NamedSeries {
+	collector := newNamedSeriesCollector(h.Cycles)
+	for i, point := range h.Points {
+		if point.Sectors != nil {
+			for _, sector := range point.Sectors.Sectors {
+				collector.add(i, sector.Id, sector.Name, sector.Total)
+			}
+		}
+	}
+	return collector.sorted()
+}
+
+// Returns the provided number of top industries for each cycle (fewer if the API returned fewer), largest first.
+// Cycles without industry data have no entries.
+func (h History) TopIndustries(count int) [][]models.Industry {
+	toReturn := make([][]models.Industry, len(h.Points))
+	for i, point := range h.Points {
+		if point.Industries == nil {
+			continue
+		}
+		industries := append([]models.Industry(nil), point.Industries.Industries...)
+		sort.SliceStable(industries, func(a, b int) bool { return industries[a].Total > industries[b].Total })
+		if len(industries) > count {
+			industries = industries[:count]
+		}
+		toReturn[i] = industries
+	}
+	return toReturn
+}
+
+func (h History) summarySeries(value func(summary *models.CandidateSummary) float64) Series {
+	series := newSeries(h.Cycles)
+	for i, point := range h.Points {
+		if point.Summary != nil {
+			series.Values[i] = value(point.Summary)
+			series.Present[i] = true
+		}
+	}
+	return series
+}
+
+func newSeries(cycles []int) Series {
+	return Series{Cycles: cycles, Values: make([]float64, len(cycles)), Present: make([]bool, len(cycles))}
+}
+
+// Builds NamedSeries for industries or sectors as they're found in each cycle.
+type namedSeriesCollector struct {
+	cycles []int
+	byCode map[string]*NamedSeries
+	totals map[string]float64
+}
+
+func newNamedSeriesCollector(cycles []int) *namedSeriesCollector {
+	return &namedSeriesCollector{cycles: cycles, byCode: map[string]*NamedSeries{}, totals: map[string]float64{}}
+}
+
+func (n *namedSeriesCollector) add(index int, code string, name string, value float64) {
+	series, found := n.byCode[code]
+	if !found {
+		series = &NamedSeries{Code: code, Name: name, Series: newSeries(n.cycles)}
+		n.byCode[code] = series
+	}
+	series.Values[index] = value
+	series.Present[index] = true
+	n.totals[code] += value
+}
+
+func (n *namedSeriesCollector) sorted() []NamedSeries {
+	var toReturn []NamedSeries
+	for _, series := range n.byCode {
+		toReturn = append(toReturn, *series)
+	}
+	sort.Slice(toReturn, func(i, j int) bool {
+		if n.totals[toReturn[i].Code] != n.totals[toReturn[j].Code] {
+			return n.totals[toReturn[i].Code] > n.totals[toReturn[j].Code]
+		}
+		return toReturn[i].Code < toReturn[j].Code
+	})
+	return toReturn
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/cycles"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+var notFound = &client.StatusError{StatusCode: 404}
+
+// An OpenSecretsClient with canned data for the cycles in summaries, and 404s for every other cycle.
+type fakeClient struct {
+	client.OpenSecretsClient
+	summaries  map[int]models.CandidateSummary
+	industries map[int][]models.Industry
+	sectors    map[int][]models.Sector
+
+	mutex     sync.Mutex
+	requested []int
+}
+
+func (f *fakeClient) GetCandidateSummary(ctx context.Context, request models.CandidateSummaryRequest) (models.CandidateSummary, error) {
+	f.mutex.Lock()
+	f.requested = append(f.requested, request.Cycle)
+	f.mutex.Unlock()
+	summary, found := f.summaries[request.Cycle]
+	if !found {
+		return models.CandidateSummary{}, notFound
+	}
+	return summary, nil
+}
+
+func (f *fakeClient) GetCandidateIndustries(ctx context.Context, request models.CandidateIndustriesRequest) (models.CandidateIndustriesSummary, error) {
+	industries, found := f.industries[request.Cycle]
+	if !found {
+		return models.CandidateIndustriesSummary{}, notFound
+	}
+	return models.CandidateIndustriesSummary{Cycle: request.Cycle, Industries: industries}, nil
+}
+
+func (f *fakeClient) GetCandidateTopSectorDetails(ctx context.Context, request models.CandidateTopSectorsRequest) (models.CandidateTopSectorDetails, error) {
+	sectors, found := f.sectors[request.Cycle]
+	if !found {
+		return models.CandidateTopSectorDetails{}, notFound
+	}
+	return models.CandidateTopSectorDetails{Cycle: request.Cycle, Sectors: sectors}, nil
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		summaries: map[int]models.CandidateSummary{
+			2018: {Total: 100, CashOnHand: 10, Debt: 1},
+			2022: {Total: 300, CashOnHand: 30, Debt: 3},
+		},
+		industries: map[int][]models.Industry{
+			2018: {{IndustryCode: "K01", IndustryName: "Lawyers", Total: 50}, {IndustryCode: "F10", IndustryName: "Real Estate", Total: 20}},
+			2022: {{IndustryCode: "F10", IndustryName: "Real Estate", Total: 90}, {IndustryCode: "H01", IndustryName: "Health", Total: 40}},
+		},
+		sectors: map[int][]models.Sector{
+			2022: {{Id: "F", Name: "Finance/Insur/RealEst", Total: 120}},
+		},
+	}
+}
+
+// Wraps a fakeClient, tracking the most calls it's had in flight at once.
+type concurrencyTrackingClient struct {
+	*fakeClient
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (c *concurrencyTrackingClient) track() func() {
+	inFlight := c.inFlight.Add(1)
+	for {
+		max := c.maxInFlight.Load()
+		if inFlight <= max || c.maxInFlight.CompareAndSwap(max, inFlight) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return func() { c.inFlight.Add(-1) }
+}
+
+func (c *concurrencyTrackingClient) GetCandidateSummary(ctx context.Context, request models.CandidateSummaryRequest) (models.CandidateSummary, error) {
+	defer c.track()()
+	return c.fakeClient.GetCandidateSummary(ctx, request)
+}
+
+func (c *concurrencyTrackingClient) GetCandidateIndustries(ctx context.Context, request models.CandidateIndustriesRequest) (models.CandidateIndustriesSummary, error) {
+	defer c.track()()
+	return c.fakeClient.GetCandidateIndustries(ctx, request)
+}
+
+func (c *concurrencyTrackingClient) GetCandidateTopSectorDetails(ctx context.Context, request models.CandidateTopSectorsRequest) (models.CandidateTopSectorDetails, error) {
+	defer c.track()()
+	return c.fakeClient.GetCandidateTopSectorDetails(ctx, request)
+}
+
+func TestGetCandidateHistory(t *testing.T) {
+	t.Run("Fetches every cycle in the range and aligns the results", func(t *testing.T) {
+		fake := newFakeClient()
+		history, err := GetCandidateHistory(context.Background(), fake, "N00007360", 2017, 2022, client.BatchOptions{})
+		test.AssertNoError(err, t)
+
+		test.AssertSliceLength(len(history.Cycles), 3, t)
+		test.AssertIntMatches(history.Cycles[0], 2018, t)
+		test.AssertIntMatches(history.Cycles[2], 2022, t)
+		test.AssertSliceLength(len(fake.requested), 3, t)
+
+		totals := history.Totals()
+		test.AssertIntMatches(int(totals.Values[0]), 100, t)
+		if totals.Present[1] {
+			t.Error("Wanted no total for 2020")
+		}
+		test.AssertIntMatches(int(totals.Values[2]), 300, t)
+		test.AssertIntMatches(int(history.CashOnHand().Values[2]), 30, t)
+		test.AssertIntMatches(int(history.Debt().Values[0]), 1, t)
+
+		if !errors.Is(history.Points[1].Errors[SummarySection], notFound) {
+			t.Errorf("Wanted the 2020 summary error kept but got %v", history.Points[1].Errors)
+		}
+	})
+	t.Run("Builds industry and sector series across cycles, largest first", func(t *testing.T) {
+		history, err := GetCandidateHistory(context.Background(), newFakeClient(), "N00007360", 2018, 2022, client.BatchOptions{})
+		test.AssertNoError(err, t)
+
+		industries := history.Industries()
+		test.AssertSliceLength(len(industries), 3, t)
+		realEstate := industries[0]
+		test.AssertStringMatches(realEstate.Code, "F10", t)
+		test.AssertIntMatches(int(realEstate.Values[0]), 20, t)
+		test.AssertIntMatches(int(realEstate.Values[2]), 90, t)
+		if industries[1].Present[2] {
+			t.Error("Wanted Lawyers missing from 2022")
+		}
+
+		sectors := history.Sectors()
+		test.AssertSliceLength(len(sectors), 1, t)
+		if sectors[0].Present[0] {
+			t.Error("Wanted no sector data for 2018")
+		}
+	})
+	t.Run("Returns each cycle's top industries", func(t *testing.T) {
+		history, _ := GetCandidateHistory(context.Background(), newFakeClient(), "N00007360", 2018, 2022, client.BatchOptions{})
+
+		top := history.TopIndustries(1)
+		test.AssertSliceLength(len(top[0]), 1, t)
+		test.AssertStringMatches(top[0][0].IndustryCode, "K01", t)
+		test.AssertSliceLength(len(top[1]), 0, t)
+		test.AssertStringMatches(top[2][0].IndustryCode, "F10", t)
+	})
+	t.Run("Runs every API call through the batch's limits", func(t *testing.T) {
+		fake := &concurrencyTrackingClient{fakeClient: newFakeClient()}
+		var total int
+		options := client.BatchOptions{Concurrency: 1, OnProgress: func(progress client.BatchProgress) { total = progress.Total }}
+		_, err := GetCandidateHistory(context.Background(), fake, "N00007360", 2018, 2022, options)
+		test.AssertNoError(err, t)
+
+		test.AssertIntMatches(total, 9, t)
+		test.AssertIntMatches(int(fake.maxInFlight.Load()), 1, t)
+	})
+	t.Run("Returns an error when no cycle has any data", func(t *testing.T) {
+		_, err := GetCandidateHistory(context.Background(), newFakeClient(), "N00007360", 2006, 2010, client.BatchOptions{})
+		if !errors.Is(err, notFound) {
+			t.Errorf("Wanted the calls' errors joined but got %v", err)
+		}
+	})
+	t.Run("Returns an error for an invalid range", func(t *testing.T) {
+		_, err := GetCandidateHistory(context.Background(), nil, "N00007360", 2022, 2018, client.BatchOptions{})
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Wanted ErrInvalidRange but got %v", err)
+		}
+		_, err = GetCandidateHistory(context.Background(), nil, "Pelosi", 2018, 2022, client.BatchOptions{})
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Wanted ErrInvalidRange but got %v", err)
+		}
+	})
+	t.Run("Returns an error for cycles outside the ones OpenSecrets has data for", func(t *testing.T) {
+		_, err := GetCandidateHistory(context.Background(), nil, "N00007360", 0, 2022, client.BatchOptions{})
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Wanted ErrInvalidRange but got %v", err)
+		}
+		_, err = GetCandidateHistory(context.Background(), nil, "N00007360", 2018, cycles.Current()+2, client.BatchOptions{})
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Wanted ErrInvalidRange but got %v", err)
+		}
+	})
+}
@@ -3,6 +3,9 @@ Package model provides data types representing requests to the OpenSecrets REST
 as the responses it returns.
 Some of the fields on these response types, like Cid, are explained in detail in the OpenSecrets OpenData
 User's Guide: https://www.opensecrets.org/resources/datadictionary/UserGuide.pdf
+
+Each response type has an Extra map holding any attributes the API returned that the type has no field for, so you
+can still get at data added after this library was written.
 */
 package models
 
@@ -16,12 +19,14 @@ type CandidateContributorSummary struct {
 	Source        string `json:"source"`       // Link to CRP data
 	Notice        string `json:"notice"`       // Required explanatory text - must be displayed with published data
 	Contributors  []CandidateContributor
+	Extra         map[string]string `json:"-"` // Response attributes this struct has no field for
 }
 
 // A contributor to a candidate.
 type CandidateContributor struct {
-	OrganizationName string  `json:"org_name"`
-	Total            float64 `json:"total,string"`  // Total from all itemized sources
-	Pacs             float64 `json:"pacs,string"`   // Total PAC contributions
-	Individuals      float64 `json:"indivs,string"` // Total individual contributions
+	OrganizationName string            `json:"org_name"`
+	Total            float64           `json:"total,string"`  // Total from all itemized sources
+	Pacs             float64           `json:"pacs,string"`   // Total PAC contributions
+	Individuals      float64           `json:"indivs,string"` // Total individual contributions
+	Extra            map[string]string `json:"-"`             // Response attributes this struct has no field for
 }
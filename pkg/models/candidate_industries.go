@@ -10,13 +10,15 @@ type CandidateIndustriesSummary struct {
 	Source        string `json:"source"`       // Link to CRP data
 	LastUpdated   string `json:"last_updated"` // Date data was last retrieved from government sources (MM/DD/YYYY)
 	Industries    []Industry
+	Extra         map[string]string `json:"-"` // Response attributes this struct has no field for
 }
 
 // An industry individuals/PACs belong to
 type Industry struct {
-	IndustryCode string  `json:"industry_code"` // CRP ID for the industry
-	IndustryName string  `json:"industry_name"`
-	Total        float64 `json:"total,string"`  // Total from all itemized sources
-	Pacs         float64 `json:"pacs,string"`   // Total PAC contributions
-	Individuals  float64 `json:"indivs,string"` // Total individual contributions
+	IndustryCode string            `json:"industry_code"` // CRP ID for the industry
+	IndustryName string            `json:"industry_name"`
+	Total        float64           `json:"total,string"`  // Total from all itemized sources
+	Pacs         float64           `json:"pacs,string"`   // Total PAC contributions
+	Individuals  float64           `json:"indivs,string"` // Total individual contributions
+	Extra        map[string]string `json:"-"`             // Response attributes this struct has no field for
 }
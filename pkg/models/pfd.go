@@ -7,7 +7,7 @@ type MemberProfile struct {
 	MemberId          string `json:"member_id"`       // CRP ID
 	NetLow            int    `json:"net_low,string"`  // Calculated low range of the person's net worth
 	NetHigh           int    `json:"net_high,string"` // Calculated high range of the person's net worth
-	PositionHeldCount int    `json:"position_held_count,string"`
+	PositionHeldCount int    `json:"positions_held_count,string"`
 	AssetCount        int    `json:"asset_count,string"`
 	AssetLow          int    `json:"asset_low,string"`  // Calculated low range value of the person's assets
 	AssetHigh         int    `json:"asset_high,string"` // Calculated high range value of the person's assets
@@ -20,29 +20,33 @@ type MemberProfile struct {
 	Assets            []Asset
 	Transactions      []Transaction
 	Positions         []Position
+	Extra             map[string]string `json:"-"` // Response attributes this struct has no field for
 }
 
 // An asset reported by a member.
 type Asset struct {
-	Name         string `json:"name"`
-	HoldingsLow  int    `json:"holdings_low,string"`  // Least the asset is worth
-	HoldingsHigh int    `json:"holdings_high,string"` // Most the asset is worth
-	Industry     string `json:"industry"`
-	Sector       string `json:"sector"`        // Sector ID
-	SubsidiaryOf string `json:"subsidiary_of"` // Parent organization
+	Name         string            `json:"name"`
+	HoldingsLow  int               `json:"holdings_low,string"`  // Least the asset is worth
+	HoldingsHigh int               `json:"holdings_high,string"` // Most the asset is worth
+	Industry     string            `json:"industry"`
+	Sector       string            `json:"sector"`        // Sector ID
+	SubsidiaryOf string            `json:"subsidiary_of"` // Parent organization
+	Extra        map[string]string `json:"-"`             // Response attributes this struct has no field for
 }
 
 // Financial transaction done during period.
 type Transaction struct {
-	AssetName         string `json:"asset_name"`
-	TransactionDate   string `json:"tx_date"`           // Mon DD YYYY
-	TransactionAction string `json:"tx_action"`         // Buy, Sold, Exchanged
-	ValueLow          int    `json:"value_low,string"`  // Minimum value of transaction
-	ValueHigh         int    `json:"value_high,string"` // Maximum value of transaction
+	AssetName         string            `json:"asset_name"`
+	TransactionDate   string            `json:"tx_date"`           // Mon DD YYYY
+	TransactionAction string            `json:"tx_action"`         // Buy, Sold, Exchanged
+	ValueLow          int               `json:"value_low,string"`  // Minimum value of transaction
+	ValueHigh         int               `json:"value_high,string"` // Maximum value of transaction
+	Extra             map[string]string `json:"-"`                 // Response attributes this struct has no field for
 }
 
 // Position held by a member.
 type Position struct {
-	Title        string `json:"title"`        // Position title
-	Organization string `json:"organization"` // Organization with which position is held
+	Title        string            `json:"title"`        // Position title
+	Organization string            `json:"organization"` // Organization with which position is held
+	Extra        map[string]string `json:"-"`            // Response attributes this struct has no field for
 }
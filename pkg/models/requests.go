@@ -1,44 +1,44 @@
 package models
 
 type LegislatorsRequest struct {
-	Id string `validate:"required"` // Required. Two-character specific state code, or CRP candidate ID.
+	Id string `validate:"required,legislatorid"` // Required. Two-character specific state code, or CRP candidate ID.
 }
 
 type MemberPFDRequest struct {
-	Cid  string `validate:"required"` // Required. CRP Candidate ID.
-	Year int    // Optional. 2013, 2014, 2015 and 2016 data provided where available.
+	Cid  string `validate:"required,cid"`      // Required. CRP Candidate ID.
+	Year int    `validate:"omitempty,pfdyear"` // Optional. 2013, 2014, 2015 and 2016 data provided where available.
 }
 
 type CandidateSummaryRequest struct {
-	Cid   string `validate:"required"` // Required. CRP Candidate ID.
-	Cycle int    // Optional; defaults to most recent cycle
+	Cid   string `validate:"required,cid"`    // Required. CRP Candidate ID.
+	Cycle int    `validate:"omitempty,cycle"` // Optional; defaults to most recent cycle
 }
 
 type CandidateContributorsRequest struct {
-	Cid   string `validate:"required"` // Required. CRP Candidate ID.
-	Cycle int    // Optional; defaults to most recent cycle
+	Cid   string `validate:"required,cid"`    // Required. CRP Candidate ID.
+	Cycle int    `validate:"omitempty,cycle"` // Optional; defaults to most recent cycle
 }
 
 type CandidateIndustriesRequest struct {
-	Cid   string `validate:"required"` // Required. CRP Candidate ID
-	Cycle int    // Optional; defaults to most recent cycle
+	Cid   string `validate:"required,cid"`    // Required. CRP Candidate ID
+	Cycle int    `validate:"omitempty,cycle"` // Optional; defaults to most recent cycle
 }
 
 type CandidateIndustryDetailsRequest struct {
-	Cid   string `validate:"required"` // Required. CRP Candidate ID
-	Ind   string `validate:"required"` // Required. A 3-character industry code
-	Cycle int    // Optional; defaults to most recent cycle
+	Cid   string `validate:"required,cid"`    // Required. CRP Candidate ID
+	Ind   string `validate:"required"`        // Required. A 3-character industry code
+	Cycle int    `validate:"omitempty,cycle"` // Optional; defaults to most recent cycle
 }
 
 type CandidateTopSectorsRequest struct {
-	Cid   string `validate:"required"` // Required. CRP Candidate ID
-	Cycle int    // Optional; defaults to most recent cycle
+	Cid   string `validate:"required,cid"`    // Required. CRP Candidate ID
+	Cycle int    `validate:"omitempty,cycle"` // Optional; defaults to most recent cycle
 }
 
 type FundraisingByCongressionalCommitteeRequest struct {
-	Committee      string `validate:"required"` // Required. Committee ID in CQ format
-	Industry       string `validate:"required"` // Required. Industry code
-	CongressNumber int    // Optional, defaults to most recent Congress
+	Committee      string `validate:"required"`           // Required. Committee ID in CQ format (see the committees package for valid codes)
+	Industry       string `validate:"required"`           // Required. Industry code
+	CongressNumber int    `validate:"omitempty,congress"` // Optional, defaults to most recent Congress
 }
 
 type OrganizationSearch struct {
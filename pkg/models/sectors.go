@@ -7,14 +7,16 @@ type CandidateTopSectorDetails struct {
 	Cycle         int    `json:"cycle,string"` // Cycle year of data being returned
 	Origin        string `json:"origin"`       // Attribution to display
 	Source        string `json:"source"`       // Link to CRP data
-	LastUpdated   string `json:"notice"`       // Date data was retrieved from government sources (MM/DD/YYYY)
+	LastUpdated   string `json:"last_updated"` // Date data was retrieved from government sources (MM/DD/YYYY)
 	Sectors       []Sector
+	Extra         map[string]string `json:"-"` // Response attributes this struct has no field for
 }
 
 type Sector struct {
-	Name        string  `json:"sector_name"`   // CRP Sector name [Agribusiness, Communic/Electronics, Construction, Defense, Energy/Nat Resource, Finance/Insur/RealEst, Health, Lawyers & Lobbyists, Transportation, Misc Business, Labor, Ideology/Single-Issue, Other]
-	Id          string  `json:"sectorid"`      // CRP's sector ID
-	Total       float64 `json:"total,string"`  // Total itemized contributions attributed
-	Pacs        float64 `json:"pacs,string"`   // Total contributed by PACs within sector
-	Individuals float64 `json:"indivs,string"` // Total contributed by individuals within sector
+	Name        string            `json:"sector_name"`   // CRP Sector name [Agribusiness, Communic/Electronics, Construction, Defense, Energy/Nat Resource, Finance/Insur/RealEst, Health, Lawyers & Lobbyists, Transportation, Misc Business, Labor, Ideology/Single-Issue, Other]
+	Id          string            `json:"sectorid"`      // CRP's sector ID
+	Total       float64           `json:"total,string"`  // Total itemized contributions attributed
+	Pacs        float64           `json:"pacs,string"`   // Total contributed by PACs within sector
+	Individuals float64           `json:"indivs,string"` // Total contributed by individuals within sector
+	Extra       map[string]string `json:"-"`             // Response attributes this struct has no field for
 }
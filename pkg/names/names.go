@@ -0,0 +1,325 @@
+/*
+Package names normalizes and fuzzy-matches the names of people and organizations, for linking your own records to
+OpenSecrets entities without calling the API.
+
+CRP standardizes spellings in ways that rarely match other data: the same person can be "Nancy Pelosi" in
+Legislator.FirstLast and "Pelosi, Nancy" in CandidateSummary.CandidateName, and organizations carry suffixes like
+"Inc" and "& Co" inconsistently. This package folds case, accents and punctuation, reorders "Last, First" names,
+understands common nicknames (Bob and Robert) and strips corporate suffixes before comparing names:
+
+	names.PersonSimilarity("Bob Casey Jr.", "Casey, Robert P") // 1
+	names.OrganizationSimilarity("Goldman Sachs", "Goldman, Sachs & Co.") // 1
+
+	matches := names.MatchPeople("Sen. Chuck Schumer", candidates) // Best first
+*/
+package names
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// A name to match against, with an ID of your choosing (e.g. a CID or CRP org ID).
+type Candidate struct {
+	ID   string
+	Name string
+}
+
+// A Candidate scored against a name, from 0 (nothing in common) to 1 (the same name once normalized).
+type Scored struct {
+	Candidate
+	Score float64
+}
+
+// Lowercases the name, folds accented letters to plain ones, turns & into "and", drops apostrophes and periods (so
+// U.S. becomes us) and replaces other punctuation with spaces.
+func Normalize(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if folded, found := accentFolds[r]; found {
+			r = folded
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			builder.WriteRune(r)
+		case r == '&':
+			builder.WriteString(" and ")
+		case r == '\'' || r == '’' || r == '.':
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// Normalizes an organization name and removes a leading "the" and corporate suffixes like Inc, Corp, LLC and Co.
+func NormalizeOrganization(name string) string {
+	words := strings.Fields(Normalize(name))
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	for len(words) > 1 {
+		last := words[len(words)-1]
+		// "& Co" normalizes to "and co"
+		if last == "co" && len(words) > 2 && words[len(words)-2] == "and" {
+			words = words[:len(words)-2]
+			continue
+		}
+		if corporateSuffixes[last] {
+			words = words[:len(words)-1]
+			continue
+		}
+		break
+	}
+	return strings.Join(words, " ")
+}
+
+// A person's name split into parts, with titles, suffixes and nicknames normalized away.
+type Person struct {
+	First  string   // Canonical first name (e.g. robert for Bob), or an initial
+	Middle []string // Middle names or initials
+	Last   string
+}
+
+/*
+Parses a person's name in either "First Middle Last" or "Last, First Middle" form. Titles (Rep., Sen., Dr.),
+generational suffixes (Jr., III) and parenthesized notes like CRP's party labels ("Nancy Pelosi (D)") are dropped, and
+common nicknames are replaced with the names they're short for.
+*/
+func ParsePerson(name string) Person {
+	if open := strings.Index(name, "("); open != -1 {
+		if close := strings.Index(name[open:], ")"); close != -1 {
+			name = name[:open] + " " + name[open+close+1:]
+		}
+	}
+
+	var beforeComma, afterComma []string
+	parts := strings.SplitN(name, ",", 2)
+	beforeComma = personWords(parts[0])
+	if len(parts) == 2 {
+		afterComma = personWords(parts[1])
+		if len(afterComma) > 0 && afterComma[0] == fifthSuffix {
+			// First Middle Last, V
+			afterComma = afterComma[1:]
+		}
+	}
+	if len(afterComma) == 0 && len(beforeComma) >= 3 && beforeComma[len(beforeComma)-1] == fifthSuffix {
+		// First Middle Last V
+		beforeComma = beforeComma[:len(beforeComma)-1]
+	}
+
+	var words []string
+	if len(beforeComma) > 0 && len(afterComma) > 0 {
+		// Last, First Middle
+		words = append(afterComma, beforeComma...)
+	} else {
+		words = append(beforeComma, afterComma...)
+	}
+
+	switch len(words) {
+	case 0:
+		return Person{}
+	case 1:
+		return Person{Last: words[0]}
+	default:
+		return Person{First: canonicalFirstName(words[0]), Middle: words[1 : len(words)-1], Last: words[len(words)-1]}
+	}
+}
+
+// Normalizes a person's name to "first middle last" form, as ParsePerson understands it.
+func NormalizePerson(name string) string {
+	person := ParsePerson(name)
+	words := append([]string{person.First}, person.Middle...)
+	return strings.Join(strings.Fields(strings.Join(append(words, person.Last), " ")), " ")
+}
+
+// Scores how alike two people's names are from 0 to 1. Last names count for more than first names; middle names are
+// ignored, and a first initial matches any first name starting with it.
+func PersonSimilarity(a string, b string) float64 {
+	personA, personB := ParsePerson(a), ParsePerson(b)
+	if personA.Last == "" || personB.Last == "" {
+		return 0
+	}
+
+	last := Similarity(personA.Last, personB.Last)
+	if personA.First == "" || personB.First == "" {
+		return last
+	}
+
+	var first float64
+	switch {
+	case personA.First == personB.First:
+		first = 1
+	case isInitialOf(personA.First, personB.First) || isInitialOf(personB.First, personA.First):
+		first = 0.8
+	default:
+		first = Similarity(personA.First, personB.First)
+	}
+
+	return 0.6*last + 0.4*first
+}
+
+// Scores how alike two organizations' names are from 0 to 1, after NormalizeOrganization.
+func OrganizationSimilarity(a string, b string) float64 {
+	return Similarity(NormalizeOrganization(a), NormalizeOrganization(b))
+}
+
+// Scores how alike two already normalized strings are from 0 to 1. Averages how many words they share with how few
+// character edits it takes to turn one into the other, so both reordered words and small misspellings score well.
+func Similarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+	return (wordOverlap(a, b) + editSimilarity(a, b)) / 2
+}
+
+// Scores each candidate against a person's name with PersonSimilarity and returns them best first.
+func MatchPeople(name string, candidates []Candidate) []Scored {
+	return match(name, candidates, PersonSimilarity)
+}
+
+// Scores each candidate against an organization's name with OrganizationSimilarity and returns them best first.
+func MatchOrganizations(name string, candidates []Candidate) []Scored {
+	return match(name, candidates, OrganizationSimilarity)
+}
+
+func match(name string, candidates []Candidate, similarity func(a string, b string) float64) []Scored {
+	scored := make([]Scored, len(candidates))
+	for i, candidate := range candidates {
+		scored[i] = Scored{Candidate: candidate, Score: similarity(name, candidate.Name)}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	return scored
+}
+
+// Normalizes part of a person's name and drops titles and generational suffixes.
+func personWords(part string) []string {
+	var words []string
+	for _, word := range strings.Fields(Normalize(part)) {
+		if !personTitles[word] && !generationalSuffixes[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func canonicalFirstName(first string) string {
+	if canonical, found := nicknames[first]; found {
+		return canonical
+	}
+	return first
+}
+
+// Reports whether initial is a single letter that starts name.
+func isInitialOf(initial string, name string) bool {
+	return len([]rune(initial)) == 1 && strings.HasPrefix(name, initial)
+}
+
+// Words more similar than this count as shared by wordOverlap, so a misspelled word still overlaps.
+const sharedWordSimilarity float64 = 0.75
+
+// Returns the Dice coefficient of the two strings' words, where each word in b is paired with its most similar unpaired
+// word in a and counts for that pair's editSimilarity if it's above sharedWordSimilarity.
+func wordOverlap(a string, b string) float64 {
+	aWords, bWords := strings.Fields(a), strings.Fields(b)
+	paired := make([]bool, len(aWords))
+	shared := 0.0
+	for _, bWord := range bWords {
+		best, bestScore := -1, sharedWordSimilarity
+		for i, aWord := range aWords {
+			if paired[i] {
+				continue
+			}
+			if score := editSimilarity(aWord, bWord); score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best != -1 {
+			paired[best] = true
+			shared += bestScore
+		}
+	}
+	return 2 * shared / float64(len(aWords)+len(bWords))
+}
+
+// Returns 1 minus the Levenshtein distance between the strings as a fraction of the longer one's length.
+func editSimilarity(a string, b string) float64 {
+	aRunes, bRunes := []rune(a), []rune(b)
+	previous := make([]int, len(bRunes)+1)
+	current := make([]int, len(bRunes)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(aRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			substitution := previous[j-1]
+			if aRunes[i-1] != bRunes[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous, current = current, previous
+	}
+
+	longest := max(len(aRunes), len(bRunes))
+	return 1 - float64(previous[len(bRunes)])/float64(longest)
+}
+
+var accentFolds = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+}
+
+var corporateSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "corp": true, "corporation": true, "co": true, "company": true,
+	"llc": true, "llp": true, "lp": true, "ltd": true, "limited": true, "plc": true, "pc": true, "pllc": true,
+	"na": true, "sa": true, "ag": true, "nv": true, "gmbh": true,
+}
+
+var personTitles = map[string]bool{
+	"rep": true, "representative": true, "sen": true, "senator": true, "gov": true, "governor": true,
+	"del": true, "delegate": true, "hon": true, "honorable": true, "dr": true, "mr": true, "mrs": true, "ms": true,
+	"pres": true, "president": true,
+}
+
+var generationalSuffixes = map[string]bool{
+	"jr": true, "sr": true, "ii": true, "iii": true, "iv": true,
+}
+
+// V is as likely to be an initial as a suffix, so ParsePerson only drops it right after a comma or at the end of a name
+// with three or more words.
+const fifthSuffix string = "v"
+
+// Common English nicknames and variant spellings, mapped to the first name they stand for.
+var nicknames = map[string]string{
+	"al": "albert", "alex": "alexander", "andy": "andrew", "drew": "andrew", "tony": "anthony",
+	"ben": "benjamin", "benny": "benjamin", "bob": "robert", "bobby": "robert", "rob": "robert", "robbie": "robert",
+	"bill": "william", "billy": "william", "will": "william", "willy": "william",
+	"chuck": "charles", "charlie": "charles", "chris": "christopher", "dan": "daniel", "danny": "daniel",
+	"dave": "david", "davey": "david", "don": "donald", "donnie": "donald", "ed": "edward", "eddie": "edward",
+	"ned": "edward", "ted": "edward", "teddy": "edward", "fred": "frederick", "freddie": "frederick",
+	"greg": "gregory", "hank": "henry", "harry": "henry", "jack": "john", "johnny": "john", "jon": "john",
+	"jim": "james", "jimmy": "james", "jerry": "gerald", "joe": "joseph", "joey": "joseph",
+	"ken": "kenneth", "kenny": "kenneth", "larry": "lawrence", "matt": "matthew", "mike": "michael",
+	"mickey": "michael", "mick": "michael", "nick": "nicholas", "pat": "patrick", "pete": "peter",
+	"rick": "richard", "ricky": "richard", "rich": "richard", "richie": "richard", "dick": "richard",
+	"ron": "ronald", "ronnie": "ronald", "sam": "samuel", "sammy": "samuel", "steve": "steven",
+	"stephen": "steven", "stevie": "steven", "tim": "timothy", "timmy": "timothy", "tom": "thomas",
+	"tommy": "thomas", "abby": "abigail", "becky": "rebecca", "beth": "elizabeth", "betsy": "elizabeth",
+	"betty": "elizabeth", "liz": "elizabeth", "lizzie": "elizabeth", "cathy": "katherine", "catherine": "katherine",
+	"kathryn": "katherine", "kathy": "katherine", "kate": "katherine", "katie": "katherine", "deb": "deborah",
+	"debbie": "deborah", "jen": "jennifer", "jenny": "jennifer", "maggie": "margaret", "meg": "margaret",
+	"peggy": "margaret", "mandy": "amanda", "patty": "patricia", "patsy": "patricia", "trish": "patricia",
+	"sue": "susan", "susie": "susan", "val": "valerie",
+}
@@ -0,0 +1,130 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("Folds case, accents and punctuation", func(t *testing.T) {
+		test.AssertStringMatches(Normalize("  Nydia M. VELÁZQUEZ-Smith "), "nydia m velazquez smith", t)
+	})
+	t.Run("Joins abbreviations and apostrophes and spells out ampersands", func(t *testing.T) {
+		test.AssertStringMatches(Normalize("U.S. O'Rourke & Sons"), "us orourke and sons", t)
+	})
+}
+
+func TestNormalizeOrganization(t *testing.T) {
+	t.Run("Strips corporate suffixes and a leading the", func(t *testing.T) {
+		test.AssertStringMatches(NormalizeOrganization("The Boeing Company"), "boeing", t)
+		test.AssertStringMatches(NormalizeOrganization("Goldman, Sachs & Co."), "goldman sachs", t)
+		test.AssertStringMatches(NormalizeOrganization("Acme Holdings, Inc. LLC"), "acme holdings", t)
+	})
+	t.Run("Leaves a name that is only a suffix alone", func(t *testing.T) {
+		test.AssertStringMatches(NormalizeOrganization("Co"), "co", t)
+	})
+}
+
+func TestParsePerson(t *testing.T) {
+	t.Run("Parses First Middle Last", func(t *testing.T) {
+		person := ParsePerson("Rep. Nancy Patricia Pelosi (D)")
+		test.AssertStringMatches(person.First, "nancy", t)
+		test.AssertSliceLength(len(person.Middle), 1, t)
+		test.AssertStringMatches(person.Last, "pelosi", t)
+	})
+	t.Run("Parses Last, First and drops suffixes", func(t *testing.T) {
+		person := ParsePerson("Casey, Bob Jr.")
+		test.AssertStringMatches(person.First, "robert", t)
+		test.AssertStringMatches(person.Last, "casey", t)
+	})
+	t.Run("Drops V after a comma or at the end of a longer name", func(t *testing.T) {
+		test.AssertStringMatches(ParsePerson("John Smith, V").Last, "smith", t)
+		test.AssertStringMatches(ParsePerson("John Q Smith V").Last, "smith", t)
+	})
+	t.Run("Keeps V that's a name or an initial", func(t *testing.T) {
+		test.AssertStringMatches(ParsePerson("John V").Last, "v", t)
+		person := ParsePerson("Smith, John V")
+		test.AssertSliceLength(len(person.Middle), 1, t)
+		test.AssertStringMatches(person.Last, "smith", t)
+	})
+	t.Run("Treats a single name as a last name", func(t *testing.T) {
+		person := ParsePerson("Pelosi")
+		test.AssertStringMatches(person.First, "", t)
+		test.AssertStringMatches(person.Last, "pelosi", t)
+	})
+	t.Run("Normalizes to first middle last", func(t *testing.T) {
+		test.AssertStringMatches(NormalizePerson("Pelosi, Nancy P"), "nancy p pelosi", t)
+	})
+}
+
+func TestPersonSimilarity(t *testing.T) {
+	t.Run("Matches reordered names", func(t *testing.T) {
+		if score := PersonSimilarity("Nancy Pelosi", "Pelosi, Nancy"); score != 1 {
+			t.Errorf("Wanted 1 but got %f", score)
+		}
+	})
+	t.Run("Matches nicknames, titles and suffixes", func(t *testing.T) {
+		if score := PersonSimilarity("Sen. Bob Casey", "Casey, Robert P Jr"); score != 1 {
+			t.Errorf("Wanted 1 but got %f", score)
+		}
+	})
+	t.Run("Scores a first initial highly", func(t *testing.T) {
+		if score := PersonSimilarity("J. Cornyn", "John Cornyn"); score < 0.9 {
+			t.Errorf("Wanted a high score but got %f", score)
+		}
+	})
+	t.Run("Scores different people low", func(t *testing.T) {
+		if score := PersonSimilarity("Nancy Pelosi", "Ted Cruz"); score > 0.3 {
+			t.Errorf("Wanted a low score but got %f", score)
+		}
+	})
+	t.Run("Scores an empty name 0", func(t *testing.T) {
+		if score := PersonSimilarity("", "Ted Cruz"); score != 0 {
+			t.Errorf("Wanted 0 but got %f", score)
+		}
+	})
+}
+
+func TestOrganizationSimilarity(t *testing.T) {
+	t.Run("Ignores suffixes and punctuation", func(t *testing.T) {
+		if score := OrganizationSimilarity("Goldman Sachs", "Goldman, Sachs & Co."); score != 1 {
+			t.Errorf("Wanted 1 but got %f", score)
+		}
+	})
+	t.Run("Scores small spelling differences highly", func(t *testing.T) {
+		if score := OrganizationSimilarity("Microsoft Corp", "Mircosoft Corporation"); score < 0.6 {
+			t.Errorf("Wanted a high score but got %f", score)
+		}
+	})
+}
+
+func TestSimilarity(t *testing.T) {
+	t.Run("Scores identical strings 1 and unrelated strings low", func(t *testing.T) {
+		if score := Similarity("general electric", "general electric"); score != 1 {
+			t.Errorf("Wanted 1 but got %f", score)
+		}
+		if score := Similarity("general electric", "walmart"); score > 0.3 {
+			t.Errorf("Wanted a low score but got %f", score)
+		}
+	})
+	t.Run("Scores an empty string 0", func(t *testing.T) {
+		if score := Similarity("", "walmart"); score != 0 {
+			t.Errorf("Wanted 0 but got %f", score)
+		}
+	})
+}
+
+func TestMatch(t *testing.T) {
+	t.Run("Returns people best first", func(t *testing.T) {
+		candidates := []Candidate{{ID: "N00033085", Name: "Cruz, Ted"}, {ID: "N00001093", Name: "Schumer, Charles E"}}
+		matches := MatchPeople("Sen. Chuck Schumer", candidates)
+		test.AssertSliceLength(len(matches), 2, t)
+		test.AssertStringMatches(matches[0].ID, "N00001093", t)
+	})
+	t.Run("Returns organizations best first", func(t *testing.T) {
+		candidates := []Candidate{{ID: "1", Name: "Sachs Electric"}, {ID: "2", Name: "Goldman Sachs & Co"}}
+		matches := MatchOrganizations("goldman sachs", candidates)
+		test.AssertStringMatches(matches[0].ID, "2", t)
+	})
+}
@@ -0,0 +1,178 @@
+/*
+Package profile assembles everything the OpenSecrets API has on a single candidate into one struct.
+
+A politician page typically needs a candidate's summary, top contributors, top industries, sector totals, personal
+financial disclosure and legislator details, which means six separate client calls. GetCandidateProfile makes them all
+at once and keeps each one's error with its section, so one missing section (say, a candidate with no financial
+disclosure) doesn't fail the whole profile:
+
+	p, err := profile.GetCandidateProfile(ctx, openSecretsClient, "N00007360", 2022)
+	if err != nil {
+		return err
+	}
+	if p.Summary != nil {
+		fmt.Println(p.Summary.CandidateName, p.Summary.Total)
+	}
+	if pfdErr := p.Errors[profile.PFDSection]; pfdErr != nil {
+		log.Printf("no financial disclosure: %v", pfdErr)
+	}
+*/
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/KiaFarhang/opensecrets/internal/ids"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/decode"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// A part of a Profile, filled in by one client call.
+type Section string
+
+const (
+	SummarySection      Section = "summary"      // GetCandidateSummary
+	ContributorsSection Section = "contributors" // GetCandidateContributors
+	IndustriesSection   Section = "industries"   // GetCandidateIndustries
+	SectorsSection      Section = "sectors"      // GetCandidateTopSectorDetails
+	PFDSection          Section = "pfd"          // GetMemberPFDProfile
+	LegislatorSection   Section = "legislator"   // GetLegislators
+)
+
+// Every Section, in the order they appear on Profile.
+var Sections = []Section{SummarySection, ContributorsSection, IndustriesSection, SectorsSection, PFDSection, LegislatorSection}
+
+/*
+Everything the API returned for a candidate. Each section is nil if its call failed, in which case its error is in
+Errors. The exception is schema drift found by strict parsing (see client.WithStrictParsing): the section keeps what
+was decoded, with the *decode.SchemaDriftError in Errors.
+
+Legislator is also nil, without an error, when the candidate isn't a current member of Congress; getLegislators only
+knows about sitting members.
+*/
+type Profile struct {
+	Cid   string
+	Cycle int // As requested; 0 means the most recent cycle
+
+	Summary      *models.CandidateSummary
+	Contributors *models.CandidateContributorSummary
+	Industries   *models.CandidateIndustriesSummary
+	Sectors      *models.CandidateTopSectorDetails
+	PFD          *models.MemberProfile // The most recent disclosure year available
+	Legislator   *models.Legislator
+
+	Errors map[Section]error // Errors of the sections whose calls failed
+}
+
+// Reports whether every section's call succeeded.
+func (p Profile) Complete() bool {
+	return len(p.Errors) == 0
+}
+
+const InvalidCidErrorMessage string = "candidate profile CID must be a CRP candidate ID (e.g. N00007360)"
+
+var ErrInvalidCid = errors.New(InvalidCidErrorMessage)
+
+/*
+Fetches every section of the candidate's profile concurrently. Pass a cycle of 0 for the most recent one.
+
+The returned error is only non-nil if cid isn't a valid CRP candidate ID, or if every section failed (in which case
+it joins their errors); otherwise check Profile.Errors for sections that couldn't be fetched.
+*/
+func GetCandidateProfile(ctx context.Context, openSecretsClient client.OpenSecretsClient, cid string, cycle int) (Profile, error) {
+	profile := Profile{Cid: cid, Cycle: cycle, Errors: map[Section]error{}}
+
+	if !ids.IsCID(cid) {
+		return profile, fmt.Errorf("%w: %q", ErrInvalidCid, cid)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	// Runs a section's call in the background, storing its result with set or its error in profile.Errors.
+	fetch := func(section Section, call func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := call()
+			if err != nil {
+				mutex.Lock()
+				profile.Errors[section] = err
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	// Each call writes a different field, so they only need the mutex for Errors.
+	fetch(SummarySection, func() error {
+		summary, err := openSecretsClient.GetCandidateSummary(ctx, models.CandidateSummaryRequest{Cid: cid, Cycle: cycle})
+		if hasData(err) {
+			profile.Summary = &summary
+		}
+		return err
+	})
+	fetch(ContributorsSection, func() error {
+		contributors, err := openSecretsClient.GetCandidateContributors(ctx, models.CandidateContributorsRequest{Cid: cid, Cycle: cycle})
+		if hasData(err) {
+			profile.Contributors = &contributors
+		}
+		return err
+	})
+	fetch(IndustriesSection, func() error {
+		industries, err := openSecretsClient.GetCandidateIndustries(ctx, models.CandidateIndustriesRequest{Cid: cid, Cycle: cycle})
+		if hasData(err) {
+			profile.Industries = &industries
+		}
+		return err
+	})
+	fetch(SectorsSection, func() error {
+		sectors, err := openSecretsClient.GetCandidateTopSectorDetails(ctx, models.CandidateTopSectorsRequest{Cid: cid, Cycle: cycle})
+		if hasData(err) {
+			profile.Sectors = &sectors
+		}
+		return err
+	})
+	fetch(PFDSection, func() error {
+		pfd, err := openSecretsClient.GetMemberPFDProfile(ctx, models.MemberPFDRequest{Cid: cid})
+		if hasData(err) {
+			profile.PFD = &pfd
+		}
+		return err
+	})
+	fetch(LegislatorSection, func() error {
+		legislators, err := openSecretsClient.GetLegislators(ctx, models.LegislatorsRequest{Id: cid})
+		if hasData(err) && len(legislators) > 0 {
+			profile.Legislator = &legislators[0]
+		}
+		return err
+	})
+
+	wg.Wait()
+
+	failed := 0
+	for _, err := range profile.Errors {
+		if !hasData(err) {
+			failed++
+		}
+	}
+	if failed == len(Sections) {
+		var sectionErrors []error
+		for _, section := range Sections {
+			sectionErrors = append(sectionErrors, fmt.Errorf("%s: %w", section, profile.Errors[section]))
+		}
+		return profile, errors.Join(sectionErrors...)
+	}
+
+	return profile, nil
+}
+
+// Reports whether a call's result is worth keeping: it succeeded, or strict parsing found schema drift, in which case
+// the client still returns what it decoded.
+func hasData(err error) bool {
+	var driftError *decode.SchemaDriftError
+	return err == nil || errors.As(err, &driftError)
+}
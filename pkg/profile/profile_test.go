@@ -0,0 +1,131 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/decode"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// An OpenSecretsClient that returns canned results, failing the methods listed in failing.
+type fakeClient struct {
+	client.OpenSecretsClient
+	failing     map[string]error
+	legislators []models.Legislator
+	cycles      chan int
+}
+
+func (f *fakeClient) fail(method string) error {
+	return f.failing[method]
+}
+
+func (f *fakeClient) GetCandidateSummary(ctx context.Context, request models.CandidateSummaryRequest) (models.CandidateSummary, error) {
+	if f.cycles != nil {
+		f.cycles <- request.Cycle
+	}
+	return models.CandidateSummary{Cid: request.Cid, CandidateName: "Pelosi, Nancy"}, f.fail("GetCandidateSummary")
+}
+
+func (f *fakeClient) GetCandidateContributors(ctx context.Context, request models.CandidateContributorsRequest) (models.CandidateContributorSummary, error) {
+	return models.CandidateContributorSummary{CandidateName: "Pelosi, Nancy"}, f.fail("GetCandidateContributors")
+}
+
+func (f *fakeClient) GetCandidateIndustries(ctx context.Context, request models.CandidateIndustriesRequest) (models.CandidateIndustriesSummary, error) {
+	return models.CandidateIndustriesSummary{CandidateName: "Pelosi, Nancy"}, f.fail("GetCandidateIndustries")
+}
+
+func (f *fakeClient) GetCandidateTopSectorDetails(ctx context.Context, request models.CandidateTopSectorsRequest) (models.CandidateTopSectorDetails, error) {
+	return models.CandidateTopSectorDetails{CandidateName: "Pelosi, Nancy"}, f.fail("GetCandidateTopSectorDetails")
+}
+
+func (f *fakeClient) GetMemberPFDProfile(ctx context.Context, request models.MemberPFDRequest) (models.MemberProfile, error) {
+	return models.MemberProfile{}, f.fail("GetMemberPFDProfile")
+}
+
+func (f *fakeClient) GetLegislators(ctx context.Context, request models.LegislatorsRequest) ([]models.Legislator, error) {
+	return f.legislators, f.fail("GetLegislators")
+}
+
+func TestGetCandidateProfile(t *testing.T) {
+	t.Run("Fills in every section", func(t *testing.T) {
+		fake := &fakeClient{legislators: []models.Legislator{{Cid: "N00007360", Office: "CA11"}}, cycles: make(chan int, 1)}
+		profile, err := GetCandidateProfile(context.Background(), fake, "N00007360", 2022)
+
+		test.AssertNoError(err, t)
+		if !profile.Complete() {
+			t.Fatalf("Wanted a complete profile but got errors %v", profile.Errors)
+		}
+		test.AssertStringMatches(profile.Summary.CandidateName, "Pelosi, Nancy", t)
+		test.AssertStringMatches(profile.Legislator.Office, "CA11", t)
+		if profile.Contributors == nil || profile.Industries == nil || profile.Sectors == nil || profile.PFD == nil {
+			t.Error("Wanted every section filled in")
+		}
+		test.AssertIntMatches(<-fake.cycles, 2022, t)
+	})
+	t.Run("Keeps each failed section's error without failing the profile", func(t *testing.T) {
+		pfdError := &client.StatusError{StatusCode: 404}
+		fake := &fakeClient{failing: map[string]error{"GetMemberPFDProfile": pfdError}}
+		profile, err := GetCandidateProfile(context.Background(), fake, "N00007360", 0)
+
+		test.AssertNoError(err, t)
+		if profile.Complete() {
+			t.Error("Wanted an incomplete profile")
+		}
+		if profile.PFD != nil {
+			t.Error("Wanted no PFD section")
+		}
+		if !errors.Is(profile.Errors[PFDSection], pfdError) {
+			t.Errorf("Wanted the PFD error but got %v", profile.Errors[PFDSection])
+		}
+		if profile.Summary == nil {
+			t.Error("Wanted the summary section despite the PFD failing")
+		}
+	})
+	t.Run("Keeps sections with schema drift alongside their errors", func(t *testing.T) {
+		driftError := &decode.SchemaDriftError{Drifts: []decode.Drift{{Unknown: []string{"new_field"}}}}
+		fake := &fakeClient{failing: map[string]error{"GetCandidateSummary": driftError}}
+		profile, err := GetCandidateProfile(context.Background(), fake, "N00007360", 0)
+
+		test.AssertNoError(err, t)
+		if profile.Summary == nil {
+			t.Fatal("Wanted the summary section despite the schema drift")
+		}
+		test.AssertStringMatches(profile.Summary.CandidateName, "Pelosi, Nancy", t)
+		if !errors.Is(profile.Errors[SummarySection], driftError) {
+			t.Errorf("Wanted the schema drift error but got %v", profile.Errors[SummarySection])
+		}
+	})
+	t.Run("Leaves the legislator section empty for candidates who aren't sitting members", func(t *testing.T) {
+		profile, err := GetCandidateProfile(context.Background(), &fakeClient{}, "N00007360", 0)
+		test.AssertNoError(err, t)
+		if profile.Legislator != nil {
+			t.Error("Wanted no legislator section")
+		}
+		if !profile.Complete() {
+			t.Errorf("Wanted a complete profile but got errors %v", profile.Errors)
+		}
+	})
+	t.Run("Returns an error when every section fails", func(t *testing.T) {
+		failure := errors.New("connection refused")
+		failing := map[string]error{}
+		for _, method := range []string{"GetCandidateSummary", "GetCandidateContributors", "GetCandidateIndustries", "GetCandidateTopSectorDetails", "GetMemberPFDProfile", "GetLegislators"} {
+			failing[method] = failure
+		}
+		profile, err := GetCandidateProfile(context.Background(), &fakeClient{failing: failing}, "N00007360", 0)
+
+		if !errors.Is(err, failure) {
+			t.Errorf("Wanted the sections' errors joined but got %v", err)
+		}
+		test.AssertIntMatches(len(profile.Errors), len(Sections), t)
+	})
+	t.Run("Returns an error for an invalid CID without calling the API", func(t *testing.T) {
+		_, err := GetCandidateProfile(context.Background(), nil, "Pelosi", 0)
+		if !errors.Is(err, ErrInvalidCid) {
+			t.Errorf("Wanted ErrInvalidCid but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+/*
+Package resolve turns a free-text organization name into the OpenSecrets organization it most likely refers to.
+
+SearchForOrganization returns every organization whose name contains the search text, in no particular order, and only
+their IDs and names. ResolveOrganization searches, ranks the results by how closely their names match, and can fetch
+summaries for the best matches:
+
+	resolution, err := resolve.ResolveOrganization(ctx, openSecretsClient, "Goldman Sachs", resolve.Options{Hydrate: 1})
+	if err != nil {
+		return err
+	}
+	if resolution.Ambiguous {
+		// Ask a person to pick from resolution.Matches
+	}
+	best := resolution.Matches[0]
+*/
+package resolve
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/names"
+)
+
+// The default for Options.AmbiguityMargin.
+const DefaultAmbiguityMargin float64 = 0.1
+
+// Configures ResolveOrganization.
+type Options struct {
+	Hydrate         int     // Fetch summaries for this many of the best matches; 0 fetches none
+	MaxResults      int     // Keep at most this many matches; 0 keeps them all
+	MinScore        float64 // Drop matches scoring below this
+	AmbiguityMargin float64 // The best match must beat the runner-up by this much not to be Ambiguous; defaults to DefaultAmbiguityMargin
+}
+
+// An organization the search returned, scored against the name being resolved.
+type Match struct {
+	Organization models.OrganizationSearchResult
+	Score        float64                     // Name similarity to the query, from 0 (nothing in common) to 1 (the same name once normalized)
+	Summary      *models.OrganizationSummary // Set for hydrated matches whose summary call succeeded
+	SummaryErr   error                       // Set for hydrated matches whose summary call failed
+}
+
+// The outcome of resolving a name.
+type Resolution struct {
+	Query   string
+	Matches []Match // Best first
+
+	// How sure the best match is the organization meant: its score minus the runner-up's (or just its score if it's the
+	// only match). 0 when there are no matches.
+	Confidence float64
+	// True when there's no clear best match: no matches at all, or a Confidence below the ambiguity margin.
+	Ambiguous bool
+}
+
+// Returns the best match, or false if there were none.
+func (r Resolution) Best() (Match, bool) {
+	if len(r.Matches) == 0 {
+		return Match{}, false
+	}
+	return r.Matches[0], true
+}
+
+const EmptyNameErrorMessage string = "organization name to resolve must not be empty"
+
+var ErrEmptyName = errors.New(EmptyNameErrorMessage)
+
+/*
+Searches for organizations matching name and ranks the results by name similarity. If options.Hydrate is set, also
+fetches summaries for that many of the best matches (concurrently); a failed summary call is recorded on its Match
+rather than failing the resolution.
+
+Returns an error if the search itself fails. Finding no organizations isn't an error; the Resolution just has no
+matches and is Ambiguous.
+*/
+func ResolveOrganization(ctx context.Context, openSecretsClient client.OpenSecretsClient, name string, options Options) (Resolution, error) {
+	if strings.TrimSpace(name) == "" {
+		return Resolution{}, ErrEmptyName
+	}
+	if options.AmbiguityMargin <= 0 {
+		options.AmbiguityMargin = DefaultAmbiguityMargin
+	}
+
+	results, err := openSecretsClient.SearchForOrganization(ctx, models.OrganizationSearch{Name: name})
+	if err != nil {
+		return Resolution{Query: name}, err
+	}
+
+	resolution := Resolution{Query: name, Matches: Rank(name, results)}
+
+	var kept []Match
+	for _, match := range resolution.Matches {
+		if match.Score >= options.MinScore {
+			kept = append(kept, match)
+		}
+	}
+	if options.MaxResults > 0 && len(kept) > options.MaxResults {
+		kept = kept[:options.MaxResults]
+	}
+	resolution.Matches = kept
+
+	switch len(kept) {
+	case 0:
+	case 1:
+		resolution.Confidence = kept[0].Score
+	default:
+		resolution.Confidence = kept[0].Score - kept[1].Score
+	}
+	resolution.Ambiguous = len(kept) == 0 || resolution.Confidence < options.AmbiguityMargin
+
+	if options.Hydrate > 0 {
+		hydrate(ctx, openSecretsClient, resolution.Matches, options.Hydrate)
+	}
+
+	return resolution, nil
+}
+
+// Scores each search result against name with names.OrganizationSimilarity and returns them best first. Results with
+// equal scores keep their order.
+func Rank(name string, results []models.OrganizationSearchResult) []Match {
+	matches := make([]Match, len(results))
+	for i, result := range results {
+		matches[i] = Match{Organization: result, Score: names.OrganizationSimilarity(name, result.Name)}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// Fetches summaries for the first count matches.
+func hydrate(ctx context.Context, openSecretsClient client.OpenSecretsClient, matches []Match, count int) {
+	if count > len(matches) {
+		count = len(matches)
+	}
+
+	requests := make([]models.OrganizationSummaryRequest, count)
+	for i := range requests {
+		requests[i] = models.OrganizationSummaryRequest{Id: matches[i].Organization.Id}
+	}
+
+	results, _ := client.RunBatch(ctx, requests, openSecretsClient.GetOrganizationSummary, client.BatchOptions{})
+	for i, result := range results {
+		if result.Err != nil {
+			matches[i].SummaryErr = result.Err
+			continue
+		}
+		summary := result.Value
+		matches[i].Summary = &summary
+	}
+}
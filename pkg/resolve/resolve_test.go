@@ -0,0 +1,103 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+type fakeClient struct {
+	client.OpenSecretsClient
+	results        []models.OrganizationSearchResult
+	searchErr      error
+	failingSummary string
+}
+
+func (f *fakeClient) SearchForOrganization(ctx context.Context, request models.OrganizationSearch) ([]models.OrganizationSearchResult, error) {
+	return f.results, f.searchErr
+}
+
+func (f *fakeClient) GetOrganizationSummary(ctx context.Context, request models.OrganizationSummaryRequest) (models.OrganizationSummary, error) {
+	if request.Id == f.failingSummary {
+		return models.OrganizationSummary{}, &client.StatusError{StatusCode: 500}
+	}
+	return models.OrganizationSummary{Id: request.Id}, nil
+}
+
+var goldmanResults = []models.OrganizationSearchResult{
+	{Id: "D000000085", Name: "Goldman Sachs Foundation"},
+	{Id: "D000000086", Name: "Goldman, Sachs & Co."},
+	{Id: "D000022222", Name: "Sachs Electric"},
+}
+
+func TestResolveOrganization(t *testing.T) {
+	t.Run("Ranks results by name similarity", func(t *testing.T) {
+		resolution, err := ResolveOrganization(context.Background(), &fakeClient{results: goldmanResults}, "Goldman Sachs & Co", Options{})
+		test.AssertNoError(err, t)
+
+		test.AssertSliceLength(len(resolution.Matches), 3, t)
+		best, _ := resolution.Best()
+		test.AssertStringMatches(best.Organization.Id, "D000000086", t)
+		test.AssertStringMatches(resolution.Matches[2].Organization.Id, "D000022222", t)
+		if best.Score != 1 {
+			t.Errorf("Wanted a score of 1 for a name differing only in punctuation but got %f", best.Score)
+		}
+	})
+	t.Run("Flags names without a clear best match as ambiguous", func(t *testing.T) {
+		results := []models.OrganizationSearchResult{{Id: "1", Name: "Acme Corp East"}, {Id: "2", Name: "Acme Corp West"}}
+		resolution, err := ResolveOrganization(context.Background(), &fakeClient{results: results}, "Acme Corp", Options{})
+		test.AssertNoError(err, t)
+
+		if !resolution.Ambiguous {
+			t.Errorf("Wanted an ambiguous resolution but got confidence %f", resolution.Confidence)
+		}
+	})
+	t.Run("Is confident in a clear best match", func(t *testing.T) {
+		resolution, _ := ResolveOrganization(context.Background(), &fakeClient{results: goldmanResults}, "Goldman Sachs & Co", Options{})
+		if resolution.Ambiguous {
+			t.Errorf("Wanted a confident resolution but got confidence %f", resolution.Confidence)
+		}
+	})
+	t.Run("Applies MinScore and MaxResults", func(t *testing.T) {
+		resolution, _ := ResolveOrganization(context.Background(), &fakeClient{results: goldmanResults}, "Goldman Sachs", Options{MinScore: 0.5, MaxResults: 1})
+		test.AssertSliceLength(len(resolution.Matches), 1, t)
+	})
+	t.Run("Hydrates the best matches with summaries, keeping summary errors per match", func(t *testing.T) {
+		fake := &fakeClient{results: goldmanResults, failingSummary: "D000000085"}
+		resolution, err := ResolveOrganization(context.Background(), fake, "Goldman Sachs & Co", Options{Hydrate: 2})
+		test.AssertNoError(err, t)
+
+		test.AssertStringMatches(resolution.Matches[0].Summary.Id, "D000000086", t)
+		test.AssertErrorExists(resolution.Matches[1].SummaryErr, t)
+		if resolution.Matches[2].Summary != nil || resolution.Matches[2].SummaryErr != nil {
+			t.Error("Wanted the third match left unhydrated")
+		}
+	})
+	t.Run("Returns an ambiguous resolution when nothing matches", func(t *testing.T) {
+		resolution, err := ResolveOrganization(context.Background(), &fakeClient{}, "Nonexistent", Options{})
+		test.AssertNoError(err, t)
+		if !resolution.Ambiguous {
+			t.Error("Wanted an empty resolution to be ambiguous")
+		}
+		if _, found := resolution.Best(); found {
+			t.Error("Wanted no best match")
+		}
+	})
+	t.Run("Returns search errors", func(t *testing.T) {
+		searchErr := errors.New("connection refused")
+		_, err := ResolveOrganization(context.Background(), &fakeClient{searchErr: searchErr}, "Goldman", Options{})
+		if !errors.Is(err, searchErr) {
+			t.Errorf("Wanted the search error but got %v", err)
+		}
+	})
+	t.Run("Returns an error for an empty name", func(t *testing.T) {
+		_, err := ResolveOrganization(context.Background(), nil, "  ", Options{})
+		if !errors.Is(err, ErrEmptyName) {
+			t.Errorf("Wanted ErrEmptyName but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+/*
+Package roster builds a national roster of the legislators the OpenSecrets API knows about.
+
+OpenSecretsClient.GetLegislators takes a single state code (or CID), so GetAllLegislators calls it for every state and
+territory in the states package, merges the results and indexes them by CID, state, party and chamber. Rosters can be
+saved and loaded as JSON so you don't have to fetch one every time your application starts:
+
+	r, err := roster.GetAllLegislators(ctx, openSecretsClient, client.BatchOptions{})
+	if err != nil {
+		return err
+	}
+
+	texasSenators := roster.InChamber(r.ByState("TX"), states.Senate)
+*/
+package roster
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/states"
+)
+
+// A legislator on the roster, along with the state and chamber they were found to represent.
+type Member struct {
+	Legislator models.Legislator `json:"legislator"`
+	State      string            `json:"state"`   // Two-character abbreviation of the state or territory
+	Chamber    states.Chamber    `json:"chamber"` // Parsed from Legislator.Office; empty if the office code isn't recognized
+}
+
+// Every legislator returned for every state and territory, indexed for lookups. A Roster is read-only once built and is
+// safe for concurrent use.
+type Roster struct {
+	Members   []Member  // Sorted by CID
+	FetchedAt time.Time // When the legislators were fetched from the API
+
+	byCid     map[string]int
+	byState   map[string][]int
+	byParty   map[string][]int
+	byChamber map[states.Chamber][]int
+}
+
+/*
+Calls GetLegislators for every state and territory and merges the results into a Roster, keeping the first entry it
+sees for each CID. The calls are made with client.RunBatch, so options controls their concurrency, rate and progress
+reporting.
+
+If some states' calls fail, GetAllLegislators returns a Roster of the states that succeeded along with a
+*client.BatchError describing the failures.
+*/
+func GetAllLegislators(ctx context.Context, openSecretsClient client.OpenSecretsClient, options client.BatchOptions) (*Roster, error) {
+	var requests []models.LegislatorsRequest
+	for _, state := range states.All() {
+		requests = append(requests, models.LegislatorsRequest{Id: state.Abbreviation})
+	}
+
+	results, err := client.RunBatch(ctx, requests, openSecretsClient.GetLegislators, options)
+
+	var members []Member
+	for _, result := range results {
+		for _, legislator := range result.Value {
+			members = append(members, newMember(legislator, result.Request.Id))
+		}
+	}
+
+	return New(members, time.Now()), err
+}
+
+// Builds a roster from the provided members, keeping the first member for each CID.
+func New(members []Member, fetchedAt time.Time) *Roster {
+	seen := map[string]bool{}
+	var deduped []Member
+	for _, member := range members {
+		if seen[member.Legislator.Cid] {
+			continue
+		}
+		seen[member.Legislator.Cid] = true
+		deduped = append(deduped, member)
+	}
+	sort.Slice(deduped, func(i, j int) bool { return deduped[i].Legislator.Cid < deduped[j].Legislator.Cid })
+
+	roster := &Roster{
+		Members:   deduped,
+		FetchedAt: fetchedAt,
+		byCid:     map[string]int{},
+		byState:   map[string][]int{},
+		byParty:   map[string][]int{},
+		byChamber: map[states.Chamber][]int{},
+	}
+	for i, member := range deduped {
+		roster.byCid[member.Legislator.Cid] = i
+		roster.byState[member.State] = append(roster.byState[member.State], i)
+		party := strings.ToUpper(member.Legislator.Party)
+		roster.byParty[party] = append(roster.byParty[party], i)
+		roster.byChamber[member.Chamber] = append(roster.byChamber[member.Chamber], i)
+	}
+	return roster
+}
+
+// Works out which state and chamber a legislator represents from their office code (e.g. TX07 or NYS1), falling back
+// to the state they were fetched for.
+func newMember(legislator models.Legislator, fetchedFor string) Member {
+	member := Member{Legislator: legislator, State: fetchedFor}
+	if district, err := states.ParseDistrict(legislator.Office); err == nil {
+		member.State = district.State.Abbreviation
+		member.Chamber = district.Chamber
+	}
+	return member
+}
+
+// Returns the member with the provided CID, or false if they aren't on the roster.
+func (r *Roster) ByCid(cid string) (Member, bool) {
+	index, found := r.byCid[cid]
+	if !found {
+		return Member{}, false
+	}
+	return r.Members[index], true
+}
+
+// Returns the members representing the provided state or territory, by abbreviation or name (e.g. TX or Texas).
+func (r *Roster) ByState(abbreviationOrName string) []Member {
+	abbreviation, found := states.Abbreviation(abbreviationOrName)
+	if !found {
+		return nil
+	}
+	return r.members(r.byState[abbreviation])
+}
+
+// Returns the members of the provided party (e.g. D, R or I), ignoring case.
+func (r *Roster) ByParty(party string) []Member {
+	return r.members(r.byParty[strings.ToUpper(party)])
+}
+
+// Returns the members of the provided chamber.
+func (r *Roster) ByChamber(chamber states.Chamber) []Member {
+	return r.members(r.byChamber[chamber])
+}
+
+// Returns the members that are in the provided chamber.
+func InChamber(members []Member, chamber states.Chamber) []Member {
+	var toReturn []Member
+	for _, member := range members {
+		if member.Chamber == chamber {
+			toReturn = append(toReturn, member)
+		}
+	}
+	return toReturn
+}
+
+func (r *Roster) members(indexes []int) []Member {
+	toReturn := make([]Member, len(indexes))
+	for i, index := range indexes {
+		toReturn[i] = r.Members[index]
+	}
+	return toReturn
+}
+
+type savedRoster struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Members   []Member  `json:"members"`
+}
+
+// Writes the roster to the provided writer as JSON. Legislator.Extra isn't saved.
+func (r *Roster) Save(writer io.Writer) error {
+	return json.NewEncoder(writer).Encode(savedRoster{FetchedAt: r.FetchedAt, Members: r.Members})
+}
+
+// Reads a roster written by Roster.Save.
+func Load(reader io.Reader) (*Roster, error) {
+	var saved savedRoster
+	if err := json.NewDecoder(reader).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return New(saved.Members, saved.FetchedAt), nil
+}
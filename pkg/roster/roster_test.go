@@ -0,0 +1,135 @@
+package roster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/states"
+)
+
+type legislatorAttributes struct {
+	cid    string
+	party  string
+	office string
+}
+
+// Fakes getLegislators responses for the provided states, and an empty roster for every other state.
+func fakeLegislatorsAPI(byState map[string][]legislatorAttributes, failing ...string) client.HttpClientFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		id := req.URL.Query().Get("id")
+		for _, failingId := range failing {
+			if id == failingId {
+				return &http.Response{StatusCode: 503, Body: io.NopCloser(strings.NewReader(""))}, nil
+			}
+		}
+
+		var legislators []string
+		for _, attributes := range byState[id] {
+			legislators = append(legislators, fmt.Sprintf(`{"@attributes":{"cid":%q,"party":%q,"office":%q,"first_elected":"2012","exit_code":"0"}}`,
+				attributes.cid, attributes.party, attributes.office))
+		}
+		body := `{"response":{"legislator":[` + strings.Join(legislators, ",") + `]}}`
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+}
+
+var texasAndNewYork = map[string][]legislatorAttributes{
+	"TX": {{"N00033085", "R", "TXS2"}, {"N00005736", "D", "TX18"}},
+	"NY": {{"N00001093", "D", "NYS1"}, {"N00033085", "R", "TXS2"}},
+	"DC": {{"N00001692", "D", "DC00"}},
+}
+
+func TestGetAllLegislators(t *testing.T) {
+	t.Run("Fetches every state and territory and dedupes by CID", func(t *testing.T) {
+		var requested []string
+		api := fakeLegislatorsAPI(texasAndNewYork)
+		openSecretsClient := client.NewOpenSecretsClientWithHttpClient("hunter2", client.HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.Query().Get("id"))
+			return api(req)
+		}))
+
+		roster, err := GetAllLegislators(context.Background(), openSecretsClient, client.BatchOptions{Concurrency: 1})
+		test.AssertNoError(err, t)
+
+		test.AssertSliceLength(len(requested), len(states.All()), t)
+		test.AssertSliceLength(len(roster.Members), 4, t)
+	})
+	t.Run("Indexes members by CID, state, party and chamber", func(t *testing.T) {
+		openSecretsClient := client.NewOpenSecretsClientWithHttpClient("hunter2", fakeLegislatorsAPI(texasAndNewYork))
+		roster, err := GetAllLegislators(context.Background(), openSecretsClient, client.BatchOptions{})
+		test.AssertNoError(err, t)
+
+		member, found := roster.ByCid("N00001093")
+		if !found {
+			t.Fatal("Wanted to find N00001093")
+		}
+		test.AssertStringMatches(member.State, "NY", t)
+		if member.Chamber != states.Senate {
+			t.Errorf("Wanted the Senate but got %s", member.Chamber)
+		}
+
+		test.AssertSliceLength(len(roster.ByState("Texas")), 2, t)
+		test.AssertSliceLength(len(roster.ByParty("d")), 3, t)
+		test.AssertSliceLength(len(roster.ByChamber(states.House)), 2, t)
+		test.AssertSliceLength(len(InChamber(roster.ByState("TX"), states.Senate)), 1, t)
+		test.AssertSliceLength(len(roster.ByState("Atlantis")), 0, t)
+	})
+	t.Run("Returns the states that succeeded along with a *client.BatchError", func(t *testing.T) {
+		openSecretsClient := client.NewOpenSecretsClientWithHttpClient("hunter2", fakeLegislatorsAPI(texasAndNewYork, "NY"))
+		roster, err := GetAllLegislators(context.Background(), openSecretsClient, client.BatchOptions{})
+
+		var batchError *client.BatchError
+		if !errors.As(err, &batchError) {
+			t.Fatalf("Wanted a *client.BatchError but got %v", err)
+		}
+		test.AssertSliceLength(len(batchError.Failed), 1, t)
+		test.AssertSliceLength(len(roster.Members), 3, t)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("Falls back to the fetched state when the office code isn't recognized", func(t *testing.T) {
+		member := newMember(models.Legislator{Cid: "N00000001", Office: "??"}, "GU")
+		test.AssertStringMatches(member.State, "GU", t)
+		test.AssertStringMatches(string(member.Chamber), "", t)
+	})
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Run("Round trips a roster through JSON", func(t *testing.T) {
+		fetchedAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+		original := New([]Member{
+			newMember(models.Legislator{Cid: "N00033085", Party: "R", Office: "TXS2", FirstElected: 2012}, "TX"),
+			newMember(models.Legislator{Cid: "N00001093", Party: "D", Office: "NYS1", FirstElected: 2008}, "NY"),
+		}, fetchedAt)
+
+		var buffer bytes.Buffer
+		test.AssertNoError(original.Save(&buffer), t)
+		loaded, err := Load(&buffer)
+		test.AssertNoError(err, t)
+
+		if !loaded.FetchedAt.Equal(fetchedAt) {
+			t.Errorf("Wanted fetched at %s but got %s", fetchedAt, loaded.FetchedAt)
+		}
+		member, found := loaded.ByCid("N00033085")
+		if !found {
+			t.Fatal("Wanted to find N00033085 in the loaded roster")
+		}
+		test.AssertIntMatches(member.Legislator.FirstElected, 2012, t)
+		test.AssertSliceLength(len(loaded.ByParty("D")), 1, t)
+	})
+	t.Run("Returns an error for invalid JSON", func(t *testing.T) {
+		_, err := Load(strings.NewReader("GARBAGE"))
+		test.AssertErrorExists(err, t)
+	})
+}
@@ -0,0 +1,201 @@
+/*
+Package states normalizes the state and district codes that appear in OpenSecrets requests and responses.
+
+The API isn't consistent about how it identifies states: CandidateSummary.State is a two-character abbreviation, while
+CommitteeMember.State and CandidateIndustryDetails.State are full names. IndependentExpenditure.District uses a
+four-character district code like NYS1 or TX07. This package converts between those forms and parses district codes.
+*/
+package states
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A U.S. state, district or territory.
+type State struct {
+	Abbreviation string // Two-character postal abbreviation
+	Name         string // Full name, as used by CommitteeMember.State and CandidateIndustryDetails.State
+	Territory    bool   // True for D.C. and the territories, which send non-voting delegates to the House
+}
+
+var all = []State{
+	{"AL", "Alabama", false},
+	{"AK", "Alaska", false},
+	{"AZ", "Arizona", false},
+	{"AR", "Arkansas", false},
+	{"CA", "California", false},
+	{"CO", "Colorado", false},
+	{"CT", "Connecticut", false},
+	{"DE", "Delaware", false},
+	{"FL", "Florida", false},
+	{"GA", "Georgia", false},
+	{"HI", "Hawaii", false},
+	{"ID", "Idaho", false},
+	{"IL", "Illinois", false},
+	{"IN", "Indiana", false},
+	{"IA", "Iowa", false},
+	{"KS", "Kansas", false},
+	{"KY", "Kentucky", false},
+	{"LA", "Louisiana", false},
+	{"ME", "Maine", false},
+	{"MD", "Maryland", false},
+	{"MA", "Massachusetts", false},
+	{"MI", "Michigan", false},
+	{"MN", "Minnesota", false},
+	{"MS", "Mississippi", false},
+	{"MO", "Missouri", false},
+	{"MT", "Montana", false},
+	{"NE", "Nebraska", false},
+	{"NV", "Nevada", false},
+	{"NH", "New Hampshire", false},
+	{"NJ", "New Jersey", false},
+	{"NM", "New Mexico", false},
+	{"NY", "New York", false},
+	{"NC", "North Carolina", false},
+	{"ND", "North Dakota", false},
+	{"OH", "Ohio", false},
+	{"OK", "Oklahoma", false},
+	{"OR", "Oregon", false},
+	{"PA", "Pennsylvania", false},
+	{"RI", "Rhode Island", false},
+	{"SC", "South Carolina", false},
+	{"SD", "South Dakota", false},
+	{"TN", "Tennessee", false},
+	{"TX", "Texas", false},
+	{"UT", "Utah", false},
+	{"VT", "Vermont", false},
+	{"VA", "Virginia", false},
+	{"WA", "Washington", false},
+	{"WV", "West Virginia", false},
+	{"WI", "Wisconsin", false},
+	{"WY", "Wyoming", false},
+	{"DC", "District of Columbia", true},
+	{"AS", "American Samoa", true},
+	{"GU", "Guam", true},
+	{"MP", "Northern Mariana Islands", true},
+	{"PR", "Puerto Rico", true},
+	{"VI", "Virgin Islands", true},
+}
+
+var byAbbreviation, byName = indexStates(all)
+
+func indexStates(states []State) (map[string]State, map[string]State) {
+	abbreviations := make(map[string]State, len(states))
+	names := make(map[string]State, len(states))
+	for _, state := range states {
+		abbreviations[state.Abbreviation] = state
+		names[strings.ToLower(state.Name)] = state
+	}
+	return abbreviations, names
+}
+
+// Returns the 50 states, D.C. and the territories.
+func All() []State {
+	toReturn := make([]State, len(all))
+	copy(toReturn, all)
+	return toReturn
+}
+
+// Finds a state by its two-character abbreviation or its full name, ignoring case and surrounding whitespace.
+func Lookup(abbreviationOrName string) (State, bool) {
+	trimmed := strings.TrimSpace(abbreviationOrName)
+	if state, found := byAbbreviation[strings.ToUpper(trimmed)]; found {
+		return state, true
+	}
+	state, found := byName[strings.ToLower(trimmed)]
+	return state, found
+}
+
+// Converts a state abbreviation or full name to its two-character abbreviation.
+func Abbreviation(abbreviationOrName string) (string, bool) {
+	state, found := Lookup(abbreviationOrName)
+	return state.Abbreviation, found
+}
+
+// Converts a state abbreviation or full name to its full name.
+func Name(abbreviationOrName string) (string, bool) {
+	state, found := Lookup(abbreviationOrName)
+	return state.Name, found
+}
+
+// Reports whether the provided string is a two-character state or territory code (case-sensitive, as the API expects).
+func IsCode(code string) bool {
+	_, found := byAbbreviation[code]
+	return found
+}
+
+// The chamber a district code refers to.
+type Chamber string
+
+const (
+	House        Chamber = "H"
+	Senate       Chamber = "S"
+	Presidential Chamber = "P"
+)
+
+// The presidential race's district code.
+const PresidentialDistrictCode string = "PRES"
+
+// A seat parsed from a four-character district code like those on IndependentExpenditure.District.
+type District struct {
+	State   State // Zero value for presidential races
+	Chamber Chamber
+	Seat    int // House district number (0 for at-large seats), or Senate seat class (1-3)
+}
+
+// Parses a four-character district code. House codes are a state abbreviation plus a two-digit district number (TX07,
+// or AK00 for an at-large seat); Senate codes are a state abbreviation, an S and the seat class (NYS1). PRES denotes the
+// presidential race.
+func ParseDistrict(code string) (District, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	if code == PresidentialDistrictCode {
+		return District{Chamber: Presidential}, nil
+	}
+
+	if len(code) != 4 {
+		return District{}, fmt.Errorf("district code %q must be four characters", code)
+	}
+
+	state, found := byAbbreviation[code[:2]]
+	if !found {
+		return District{}, fmt.Errorf("district code %q has an unknown state abbreviation", code)
+	}
+
+	if code[2] == 'S' {
+		seat, err := strconv.Atoi(code[3:])
+		if err != nil || seat < 1 || seat > 3 {
+			return District{}, fmt.Errorf("district code %q has an invalid Senate seat class", code)
+		}
+		return District{State: state, Chamber: Senate, Seat: seat}, nil
+	}
+
+	if !isDigit(code[2]) || !isDigit(code[3]) {
+		return District{}, fmt.Errorf("district code %q has an invalid House district number", code)
+	}
+	seat := int(code[2]-'0')*10 + int(code[3]-'0')
+	return District{State: state, Chamber: House, Seat: seat}, nil
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// Returns the district's four-character code.
+func (d District) String() string {
+	switch d.Chamber {
+	case Presidential:
+		return PresidentialDistrictCode
+	case Senate:
+		return fmt.Sprintf("%sS%d", d.State.Abbreviation, d.Seat)
+	default:
+		return fmt.Sprintf("%s%02d", d.State.Abbreviation, d.Seat)
+	}
+}
+
+// Reports whether the district is a House at-large seat.
+func (d District) AtLarge() bool {
+	return d.Chamber == House && d.Seat == 0
+}
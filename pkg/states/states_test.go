@@ -0,0 +1,84 @@
+package states
+
+import (
+	"testing"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+)
+
+func TestLookup(t *testing.T) {
+	t.Run("Finds a state by abbreviation", func(t *testing.T) {
+		state, found := Lookup("tx")
+		if !found {
+			t.Fatal("Wanted to find tx but didn't")
+		}
+		test.AssertStringMatches(state.Name, "Texas", t)
+	})
+	t.Run("Finds a state by full name", func(t *testing.T) {
+		state, found := Lookup(" new york ")
+		if !found {
+			t.Fatal("Wanted to find new york but didn't")
+		}
+		test.AssertStringMatches(state.Abbreviation, "NY", t)
+	})
+	t.Run("Returns false for an unknown state", func(t *testing.T) {
+		_, found := Lookup("Atlantis")
+		if found {
+			t.Error("Wanted no state for Atlantis but found one")
+		}
+	})
+}
+
+func TestAbbreviationAndName(t *testing.T) {
+	t.Run("Converts a full name to an abbreviation", func(t *testing.T) {
+		abbreviation, _ := Abbreviation("California")
+		test.AssertStringMatches(abbreviation, "CA", t)
+	})
+	t.Run("Converts an abbreviation to a full name", func(t *testing.T) {
+		name, _ := Name("DC")
+		test.AssertStringMatches(name, "District of Columbia", t)
+	})
+}
+
+func TestParseDistrict(t *testing.T) {
+	t.Run("Parses a Senate seat", func(t *testing.T) {
+		district, err := ParseDistrict("NYS1")
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(district.State.Abbreviation, "NY", t)
+		test.AssertStringMatches(string(district.Chamber), string(Senate), t)
+		test.AssertIntMatches(district.Seat, 1, t)
+	})
+	t.Run("Parses a House district", func(t *testing.T) {
+		district, err := ParseDistrict("TX07")
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(string(district.Chamber), string(House), t)
+		test.AssertIntMatches(district.Seat, 7, t)
+	})
+	t.Run("Parses an at-large seat", func(t *testing.T) {
+		district, err := ParseDistrict("AK00")
+		test.AssertNoError(err, t)
+		if !district.AtLarge() {
+			t.Error("Wanted AK00 to be at-large")
+		}
+	})
+	t.Run("Parses the presidential race", func(t *testing.T) {
+		district, err := ParseDistrict("PRES")
+		test.AssertNoError(err, t)
+		test.AssertStringMatches(string(district.Chamber), string(Presidential), t)
+	})
+	t.Run("Round trips through String", func(t *testing.T) {
+		for _, code := range []string{"NYS1", "TX07", "AK00", "PRES"} {
+			district, err := ParseDistrict(code)
+			test.AssertNoError(err, t)
+			test.AssertStringMatches(district.String(), code, t)
+		}
+	})
+	t.Run("Returns an error for malformed codes", func(t *testing.T) {
+		for _, code := range []string{"", "NY1", "ZZ01", "NYS4", "NYXX", "TX-1", "TX+1", "TX1"} {
+			_, err := ParseDistrict(code)
+			if err == nil {
+				t.Errorf("Wanted an error for %q", code)
+			}
+		}
+	})
+}
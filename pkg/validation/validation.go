@@ -0,0 +1,168 @@
+/*
+Package validation checks requests from the models package before they're sent to the OpenSecrets API.
+
+Beyond making sure required fields are present, it checks that CIDs look like CRP candidate IDs, that cycles are even
+election years OpenSecrets has data for, that PFD years are ones the API provides and that Congress numbers are real.
+Failures come back as ValidationErrors, a list of FieldErrors describing each problem.
+*/
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/ids"
+	"github.com/KiaFarhang/opensecrets/pkg/cycles"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+	"github.com/KiaFarhang/opensecrets/pkg/states"
+	"github.com/go-playground/validator/v10"
+)
+
+// The earliest election cycle OpenSecrets provides data for.
+const MinCycle int = 1990
+
+// The Congress that sat during the MinCycle cycle.
+const MinCongress int = 101
+
+// The years the memPFDProfile method provides personal financial disclosure data for.
+var AvailablePFDYears = []int{2013, 2014, 2015, 2016}
+
+const InvalidLegislatorsIdErrorMessage string = "legislators request ID must be a two-character state code or a CRP candidate ID"
+
+var ErrInvalidLegislatorsId = errors.New(InvalidLegislatorsIdErrorMessage)
+
+// Describes a single field that failed validation.
+type FieldError struct {
+	Request string      // Name of the request type (e.g. CandidateSummaryRequest)
+	Field   string      // Name of the field that failed validation (e.g. Cid)
+	Tag     string      // Validation rule that failed (e.g. required, cid, cycle)
+	Value   interface{} // The value that failed validation
+	Message string      // Human-readable description of the problem
+}
+
+func (f FieldError) Error() string {
+	return f.Message
+}
+
+// Every field that failed validation on a request.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, fieldError := range v {
+		messages[i] = fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// A Validator checks request structs against their validate tags. It's safe for concurrent use.
+type Validator struct {
+	validate *validator.Validate
+	now      func() time.Time
+}
+
+// Construct a Validator with the custom rules used by the models package registered.
+func New() *Validator {
+	v := &Validator{validate: validator.New(), now: time.Now}
+
+	v.validate.RegisterValidation("cid", func(fl validator.FieldLevel) bool {
+		return ids.IsCID(fl.Field().String())
+	})
+	v.validate.RegisterValidation("cycle", func(fl validator.FieldLevel) bool {
+		return v.isValidCycle(int(fl.Field().Int()))
+	})
+	v.validate.RegisterValidation("pfdyear", func(fl validator.FieldLevel) bool {
+		return isAvailablePFDYear(int(fl.Field().Int()))
+	})
+	v.validate.RegisterValidation("congress", func(fl validator.FieldLevel) bool {
+		return v.isValidCongress(int(fl.Field().Int()))
+	})
+	v.validate.RegisterValidation("legislatorid", func(fl validator.FieldLevel) bool {
+		return IsLegislatorID(fl.Field().String())
+	})
+
+	return v
+}
+
+// Validates the provided request struct. Returns nil if it's valid, ValidationErrors if any fields failed, or another
+// error if the value passed can't be validated (e.g. it isn't a struct).
+func (v *Validator) Struct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validatorErrors validator.ValidationErrors
+	if !errors.As(err, &validatorErrors) {
+		return err
+	}
+
+	toReturn := make(ValidationErrors, len(validatorErrors))
+	for i, fieldError := range validatorErrors {
+		toReturn[i] = FieldError{
+			Request: strings.SplitN(fieldError.StructNamespace(), ".", 2)[0],
+			Field:   fieldError.StructField(),
+			Tag:     fieldError.Tag(),
+			Value:   fieldError.Value(),
+			Message: v.message(fieldError),
+		}
+	}
+	return toReturn
+}
+
+func (v *Validator) message(fieldError validator.FieldError) string {
+	field := fieldError.StructField()
+	switch fieldError.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "cid":
+		return fmt.Sprintf("%s must be a CRP candidate ID like N00007360, got %q", field, fieldError.Value())
+	case "cycle":
+		return fmt.Sprintf("%s must be an even election year between %d and %d, got %v", field, MinCycle, v.currentCycle(), fieldError.Value())
+	case "pfdyear":
+		return fmt.Sprintf("%s must be one of %v, got %v", field, AvailablePFDYears, fieldError.Value())
+	case "congress":
+		return fmt.Sprintf("%s must be between %d and %d, got %v", field, MinCongress, v.currentCongress(), fieldError.Value())
+	case "legislatorid":
+		return fmt.Sprintf("%s must be a two-character state code or a CRP candidate ID, got %q", field, fieldError.Value())
+	default:
+		return fmt.Sprintf("%s failed %s validation", field, fieldError.Tag())
+	}
+}
+
+// Reports whether the provided string can be a LegislatorsRequest.Id: a two-character state code or a CRP candidate ID.
+func IsLegislatorID(id string) bool {
+	return states.IsCode(id) || ids.IsCID(id)
+}
+
+// Checks only that the request's Id is a two-character state code or a CRP candidate ID, without building a Validator.
+func ValidateLegislatorsRequest(request models.LegislatorsRequest) error {
+	if IsLegislatorID(request.Id) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidLegislatorsId, request.Id)
+}
+
+func (v *Validator) isValidCycle(cycle int) bool {
+	return cycle%2 == 0 && cycle >= MinCycle && cycle <= v.currentCycle()
+}
+
+func (v *Validator) isValidCongress(congress int) bool {
+	return congress >= MinCongress && congress <= v.currentCongress()
+}
+
+func (v *Validator) currentCycle() int {
+	return cycles.CycleForDate(v.now())
+}
+
+func (v *Validator) currentCongress() int {
+	return cycles.CongressForCycle(v.currentCycle())
+}
+
+func isAvailablePFDYear(year int) bool {
+	index := sort.SearchInts(AvailablePFDYears, year)
+	return index < len(AvailablePFDYears) && AvailablePFDYears[index] == year
+}
@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+func newTestValidator() *Validator {
+	v := New()
+	v.now = func() time.Time { return time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC) }
+	return v
+}
+
+func TestStruct(t *testing.T) {
+	t.Run("Returns nil for a valid request", func(t *testing.T) {
+		err := newTestValidator().Struct(models.CandidateSummaryRequest{Cid: "N00007360", Cycle: 2022})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Reports a missing required field", func(t *testing.T) {
+		err := newTestValidator().Struct(models.OrganizationSearch{})
+		fieldErrors := assertValidationErrors(err, 1, t)
+		test.AssertStringMatches(fieldErrors[0].Request, "OrganizationSearch", t)
+		test.AssertStringMatches(fieldErrors[0].Field, "Name", t)
+		test.AssertStringMatches(fieldErrors[0].Tag, "required", t)
+		test.AssertStringMatches(fieldErrors[0].Message, "Name is required", t)
+	})
+	t.Run("Rejects a malformed CID", func(t *testing.T) {
+		err := newTestValidator().Struct(models.CandidateSummaryRequest{Cid: "Pelosi"})
+		fieldErrors := assertValidationErrors(err, 1, t)
+		test.AssertStringMatches(fieldErrors[0].Tag, "cid", t)
+	})
+	t.Run("Rejects odd, early and future cycles", func(t *testing.T) {
+		for _, cycle := range []int{2021, 1988, 2026} {
+			err := newTestValidator().Struct(models.CandidateIndustriesRequest{Cid: "N00007360", Cycle: cycle})
+			fieldErrors := assertValidationErrors(err, 1, t)
+			test.AssertStringMatches(fieldErrors[0].Tag, "cycle", t)
+		}
+	})
+	t.Run("Accepts the current cycle during an odd year", func(t *testing.T) {
+		err := newTestValidator().Struct(models.CandidateIndustriesRequest{Cid: "N00007360", Cycle: 2024})
+		test.AssertNoError(err, t)
+	})
+	t.Run("Rejects unavailable PFD years", func(t *testing.T) {
+		err := newTestValidator().Struct(models.MemberPFDRequest{Cid: "N00007360", Year: 2020})
+		fieldErrors := assertValidationErrors(err, 1, t)
+		test.AssertStringMatches(fieldErrors[0].Message, "Year must be one of [2013 2014 2015 2016], got 2020", t)
+	})
+	t.Run("Rejects Congress numbers outside the data range", func(t *testing.T) {
+		for _, congress := range []int{100, 119} {
+			request := models.FundraisingByCongressionalCommitteeRequest{Committee: "HARM", Industry: "F10", CongressNumber: congress}
+			fieldErrors := assertValidationErrors(newTestValidator().Struct(request), 1, t)
+			test.AssertStringMatches(fieldErrors[0].Tag, "congress", t)
+		}
+	})
+	t.Run("Accepts state codes and CIDs for legislator requests", func(t *testing.T) {
+		for _, id := range []string{"TX", "N00007360"} {
+			test.AssertNoError(newTestValidator().Struct(models.LegislatorsRequest{Id: id}), t)
+		}
+		fieldErrors := assertValidationErrors(newTestValidator().Struct(models.LegislatorsRequest{Id: "Texas"}), 1, t)
+		test.AssertStringMatches(fieldErrors[0].Tag, "legislatorid", t)
+	})
+	t.Run("Reports every failing field", func(t *testing.T) {
+		err := newTestValidator().Struct(models.CandidateIndustryDetailsRequest{Cid: "bad", Cycle: 2021})
+		assertValidationErrors(err, 3, t)
+	})
+}
+
+func assertValidationErrors(err error, wantedCount int, t *testing.T) ValidationErrors {
+	t.Helper()
+	var fieldErrors ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		t.Fatalf("Wanted ValidationErrors but got %v", err)
+	}
+	test.AssertSliceLength(len(fieldErrors), wantedCount, t)
+	return fieldErrors
+}
+
+func TestValidateLegislatorsRequest(t *testing.T) {
+	t.Run("Accepts a state code", func(t *testing.T) {
+		test.AssertNoError(ValidateLegislatorsRequest(models.LegislatorsRequest{Id: "TX"}), t)
+	})
+	t.Run("Accepts a CID", func(t *testing.T) {
+		test.AssertNoError(ValidateLegislatorsRequest(models.LegislatorsRequest{Id: "N00007360"}), t)
+	})
+	t.Run("Rejects anything else", func(t *testing.T) {
+		for _, id := range []string{"", "tx", "Texas", "N123"} {
+			err := ValidateLegislatorsRequest(models.LegislatorsRequest{Id: id})
+			if !errors.Is(err, ErrInvalidLegislatorsId) {
+				t.Errorf("Wanted ErrInvalidLegislatorsId for %q", id)
+			}
+		}
+	})
+}
@@ -0,0 +1,321 @@
+/*
+Package watch turns GetLatestIndependentExpenditures into a feed of new transactions.
+
+The API only returns the latest 50 independent expenditures, updated four times a day, so catching every one means
+polling and working out which transactions you've already seen. A Watcher polls on an interval, identifies each
+transaction by a stable Key, delivers only the ones it hasn't delivered before, and saves a Checkpoint to a Store after
+each delivery so a restarted process picks up where the last one left off:
+
+	watcher, err := watch.New(openSecretsClient, watch.Options{Store: watch.FileStore{Path: "indexp.json"}})
+	if err != nil {
+		return err
+	}
+
+	err = watcher.Run(ctx, func(ctx context.Context, expenditures []models.IndependentExpenditure) error {
+		return saveToDatabase(ctx, expenditures) // Returning an error redelivers these on the next poll
+	})
+*/
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// The default for Options.Interval. The API updates independent expenditures four times a day, so polling hourly
+// delivers new ones within an hour of their appearing.
+const DefaultInterval time.Duration = time.Hour
+
+// The default for Options.MaxSeen; far more than the 50 transactions the API returns at once.
+const DefaultMaxSeen int = 1000
+
+// The layout of IndependentExpenditure.Date, less its optional fractional seconds.
+const dateLayout string = "2006-01-02 15:04:05"
+
+// Configures a Watcher.
+type Options struct {
+	Interval time.Duration // Time between polls; defaults to DefaultInterval
+	Store    Store         // Where the checkpoint is loaded from and saved to; if nil, it's only kept in memory
+	MaxSeen  int           // Most transaction keys to remember; defaults to DefaultMaxSeen
+
+	// If set and there's no saved checkpoint, the transactions returned by the first poll are marked seen without being
+	// delivered, so a new Watcher only delivers transactions that appear after it starts.
+	SkipExisting bool
+	// Optional; called with each poll's error (from the API, delivery or the Store). The Watcher keeps polling.
+	OnError func(err error)
+}
+
+/*
+What a Watcher has delivered: the keys of the transactions it's seen, oldest first, and the latest date among them (its
+high-water mark). Transactions are deduplicated by key rather than date, so ones the API reports late with an earlier
+date are still delivered; Latest is informational only and never used to decide what to deliver.
+*/
+type Checkpoint struct {
+	Latest time.Time `json:"latest"` // Zero until a transaction with a parseable date is seen
+	Seen   []string  `json:"seen"`
+}
+
+// Persists a Watcher's Checkpoint between runs.
+type Store interface {
+	// Returns the saved checkpoint, or a zero Checkpoint if none has been saved.
+	Load() (Checkpoint, error)
+	Save(checkpoint Checkpoint) error
+}
+
+// A Store that keeps the checkpoint in a JSON file, replacing it atomically on each save.
+type FileStore struct {
+	Path string
+}
+
+func (f FileStore) Load() (Checkpoint, error) {
+	var checkpoint Checkpoint
+	contents, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return checkpoint, err
+	}
+	err = json.Unmarshal(contents, &checkpoint)
+	return checkpoint, err
+}
+
+func (f FileStore) Save(checkpoint Checkpoint) error {
+	contents, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(contents); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), f.Path)
+}
+
+/*
+Returns a stable key identifying the transaction: a hash of every field the API returns except Origin and Source, which
+are the same for every transaction. Two polls returning the same transaction give it the same key.
+*/
+func Key(expenditure models.IndependentExpenditure) string {
+	fields := []string{
+		expenditure.CommitteeId,
+		expenditure.SupportOrOppose,
+		expenditure.CandidateName,
+		expenditure.District,
+		strconv.FormatFloat(expenditure.Amount, 'f', 2, 64),
+		expenditure.Note,
+		expenditure.Party,
+		expenditure.Payee,
+		expenditure.Date,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Polls for independent expenditures and delivers the new ones. Safe for concurrent use, though polls never overlap.
+type Watcher struct {
+	client  client.OpenSecretsClient
+	options Options
+
+	mutex      sync.Mutex
+	checkpoint Checkpoint
+	seen       map[string]bool
+}
+
+// Builds a Watcher, loading its checkpoint from options.Store if one is set.
+func New(openSecretsClient client.OpenSecretsClient, options Options) (*Watcher, error) {
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+	if options.MaxSeen <= 0 {
+		options.MaxSeen = DefaultMaxSeen
+	}
+
+	watcher := &Watcher{client: openSecretsClient, options: options, seen: map[string]bool{}}
+	if options.Store != nil {
+		checkpoint, err := options.Store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading independent expenditure checkpoint: %w", err)
+		}
+		watcher.checkpoint = checkpoint
+		for _, key := range checkpoint.Seen {
+			watcher.seen[key] = true
+		}
+	}
+	return watcher, nil
+}
+
+// Returns a copy of the Watcher's current checkpoint.
+func (w *Watcher) Checkpoint() Checkpoint {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return Checkpoint{Latest: w.checkpoint.Latest, Seen: append([]string(nil), w.checkpoint.Seen...)}
+}
+
+/*
+Fetches the latest independent expenditures once and passes the ones not yet seen to deliver, oldest first. deliver
+isn't called if there are none.
+
+The checkpoint only advances, and is only saved, if deliver returns nil; otherwise the same transactions are delivered
+again on the next poll. Returns an error if the API call, deliver or saving the checkpoint fails.
+*/
+func (w *Watcher) Poll(ctx context.Context, deliver func(ctx context.Context, expenditures []models.IndependentExpenditure) error) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	expenditures, err := w.client.GetLatestIndependentExpenditures(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, len(expenditures))
+	var fresh []models.IndependentExpenditure
+	freshKeys := map[string]bool{}
+	for i, expenditure := range expenditures {
+		keys[i] = Key(expenditure)
+		if !w.seen[keys[i]] && !freshKeys[keys[i]] {
+			fresh = append(fresh, expenditure)
+			freshKeys[keys[i]] = true
+		}
+	}
+
+	skipExisting := w.options.SkipExisting && len(w.checkpoint.Seen) == 0
+	if len(fresh) > 0 && !skipExisting {
+		sortOldestFirst(fresh)
+		if err := deliver(ctx, fresh); err != nil {
+			return err
+		}
+	}
+
+	w.advance(keys, expenditures)
+	if w.options.Store != nil {
+		if err := w.options.Store.Save(w.checkpoint); err != nil {
+			return fmt.Errorf("saving independent expenditure checkpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+/*
+Polls immediately and then every Options.Interval until ctx is done, passing new transactions to deliver as Poll
+does. Poll errors are passed to Options.OnError rather than stopping the Watcher. Returns ctx's error.
+*/
+func (w *Watcher) Run(ctx context.Context, deliver func(ctx context.Context, expenditures []models.IndependentExpenditure) error) error {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx, deliver); err != nil && ctx.Err() == nil && w.options.OnError != nil {
+			w.options.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Runs the Watcher in the background and sends each new transaction on the returned channel, oldest first. A
+transaction counts as delivered once it's been received from the channel; if ctx is done first, the rest of that poll
+is redelivered after a restart. The channel is closed when ctx is done.
+*/
+func (w *Watcher) Watch(ctx context.Context) <-chan models.IndependentExpenditure {
+	channel := make(chan models.IndependentExpenditure)
+	go func() {
+		defer close(channel)
+		w.Run(ctx, func(ctx context.Context, expenditures []models.IndependentExpenditure) error {
+			for _, expenditure := range expenditures {
+				select {
+				case channel <- expenditure:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}()
+	return channel
+}
+
+// Marks the poll's transactions seen, keeping the keys of the current poll as the most recent so they're never
+// forgotten while the API still returns them, and forgetting the oldest keys past Options.MaxSeen.
+func (w *Watcher) advance(keys []string, expenditures []models.IndependentExpenditure) {
+	current := map[string]bool{}
+	for _, key := range keys {
+		current[key] = true
+	}
+
+	seen := make([]string, 0, len(w.checkpoint.Seen)+len(keys))
+	for _, key := range w.checkpoint.Seen {
+		if !current[key] {
+			seen = append(seen, key)
+		}
+	}
+	for _, key := range keys {
+		if current[key] {
+			seen = append(seen, key)
+			delete(current, key)
+		}
+	}
+	if len(seen) > w.options.MaxSeen {
+		seen = seen[len(seen)-w.options.MaxSeen:]
+	}
+
+	w.checkpoint.Seen = seen
+	w.seen = map[string]bool{}
+	for _, key := range seen {
+		w.seen[key] = true
+	}
+
+	for _, expenditure := range expenditures {
+		if date, ok := parseDate(expenditure.Date); ok && date.After(w.checkpoint.Latest) {
+			w.checkpoint.Latest = date
+		}
+	}
+}
+
+// Sorts by date, oldest first, with unparseable dates last. The API returns the newest first, so transactions with the
+// same or unparseable dates are reversed to keep them oldest first too.
+func sortOldestFirst(expenditures []models.IndependentExpenditure) {
+	for i, j := 0, len(expenditures)-1; i < j; i, j = i+1, j-1 {
+		expenditures[i], expenditures[j] = expenditures[j], expenditures[i]
+	}
+	sort.SliceStable(expenditures, func(i, j int) bool {
+		dateI, okI := parseDate(expenditures[i].Date)
+		dateJ, okJ := parseDate(expenditures[j].Date)
+		if okI != okJ {
+			return okI
+		}
+		return okI && dateI.Before(dateJ)
+	})
+}
+
+func parseDate(date string) (time.Time, bool) {
+	parsed, err := time.Parse(dateLayout, strings.TrimSpace(date))
+	return parsed, err == nil
+}
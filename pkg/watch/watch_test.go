@@ -0,0 +1,241 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KiaFarhang/opensecrets/internal/test"
+	"github.com/KiaFarhang/opensecrets/pkg/client"
+	"github.com/KiaFarhang/opensecrets/pkg/models"
+)
+
+// Returns each of its feeds in turn, repeating the last one.
+type fakeClient struct {
+	client.OpenSecretsClient
+	mutex sync.Mutex
+	feeds [][]models.IndependentExpenditure
+	err   error
+	polls int
+}
+
+func (f *fakeClient) GetLatestIndependentExpenditures(ctx context.Context) ([]models.IndependentExpenditure, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.polls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	feed := f.feeds[min(f.polls, len(f.feeds))-1]
+	return feed, nil
+}
+
+func expenditure(payee string, date string) models.IndependentExpenditure {
+	return models.IndependentExpenditure{CommitteeId: "C00000001", Payee: payee, Amount: 100, Date: date}
+}
+
+var (
+	first  = expenditure("First", "2022-10-01 09:00:00.00")
+	second = expenditure("Second", "2022-10-02 09:00:00.00")
+	third  = expenditure("Third", "2022-10-03 09:00:00.00")
+)
+
+// Collects delivered payees.
+type collector struct {
+	payees []string
+}
+
+func (c *collector) deliver(ctx context.Context, expenditures []models.IndependentExpenditure) error {
+	for _, expenditure := range expenditures {
+		c.payees = append(c.payees, expenditure.Payee)
+	}
+	return nil
+}
+
+type memoryStore struct {
+	checkpoint Checkpoint
+	saves      int
+}
+
+func (m *memoryStore) Load() (Checkpoint, error) { return m.checkpoint, nil }
+func (m *memoryStore) Save(checkpoint Checkpoint) error {
+	m.checkpoint = checkpoint
+	m.saves++
+	return nil
+}
+
+func TestKey(t *testing.T) {
+	t.Run("Is stable and distinguishes transactions", func(t *testing.T) {
+		test.AssertStringMatches(Key(first), Key(expenditure("First", "2022-10-01 09:00:00.00")), t)
+		if Key(first) == Key(second) {
+			t.Error("Wanted different transactions to have different keys")
+		}
+	})
+}
+
+func TestPoll(t *testing.T) {
+	t.Run("Delivers only new transactions, oldest first", func(t *testing.T) {
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{second, first}, {third, second, first}}}
+		watcher, err := New(fake, Options{})
+		test.AssertNoError(err, t)
+
+		var delivered collector
+		test.AssertNoError(watcher.Poll(context.Background(), delivered.deliver), t)
+		test.AssertNoError(watcher.Poll(context.Background(), delivered.deliver), t)
+		test.AssertNoError(watcher.Poll(context.Background(), delivered.deliver), t)
+
+		test.AssertSliceLength(len(delivered.payees), 3, t)
+		test.AssertStringMatches(delivered.payees[0], "First", t)
+		test.AssertStringMatches(delivered.payees[2], "Third", t)
+
+		latest := watcher.Checkpoint().Latest
+		if !latest.Equal(time.Date(2022, 10, 3, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("Wanted the latest date as the high-water mark but got %v", latest)
+		}
+	})
+	t.Run("Delivers transactions with unparseable dates last", func(t *testing.T) {
+		undated := expenditure("Undated", "")
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{third, undated, second, first}}}
+		watcher, _ := New(fake, Options{})
+
+		var delivered collector
+		test.AssertNoError(watcher.Poll(context.Background(), delivered.deliver), t)
+
+		test.AssertSliceLength(len(delivered.payees), 4, t)
+		test.AssertStringMatches(delivered.payees[0], "First", t)
+		test.AssertStringMatches(delivered.payees[2], "Third", t)
+		test.AssertStringMatches(delivered.payees[3], "Undated", t)
+	})
+	t.Run("Delivers a transaction the feed repeats once", func(t *testing.T) {
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{second, first, first}}}
+		watcher, _ := New(fake, Options{})
+
+		var delivered collector
+		test.AssertNoError(watcher.Poll(context.Background(), delivered.deliver), t)
+
+		test.AssertSliceLength(len(delivered.payees), 2, t)
+		test.AssertSliceLength(len(watcher.Checkpoint().Seen), 2, t)
+	})
+	t.Run("Redelivers transactions whose delivery failed", func(t *testing.T) {
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{first}}}
+		watcher, _ := New(fake, Options{})
+
+		err := watcher.Poll(context.Background(), func(ctx context.Context, expenditures []models.IndependentExpenditure) error {
+			return errors.New("database down")
+		})
+		test.AssertErrorExists(err, t)
+
+		var delivered collector
+		test.AssertNoError(watcher.Poll(context.Background(), delivered.deliver), t)
+		test.AssertSliceLength(len(delivered.payees), 1, t)
+	})
+	t.Run("Skips the first poll's transactions if asked", func(t *testing.T) {
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{second, first}, {third, second, first}}}
+		watcher, _ := New(fake, Options{SkipExisting: true})
+
+		var delivered collector
+		watcher.Poll(context.Background(), delivered.deliver)
+		watcher.Poll(context.Background(), delivered.deliver)
+
+		test.AssertSliceLength(len(delivered.payees), 1, t)
+		test.AssertStringMatches(delivered.payees[0], "Third", t)
+	})
+	t.Run("Returns API errors", func(t *testing.T) {
+		watcher, _ := New(&fakeClient{err: &client.StatusError{StatusCode: 503}}, Options{})
+		test.AssertErrorExists(watcher.Poll(context.Background(), (&collector{}).deliver), t)
+	})
+	t.Run("Forgets the oldest keys past MaxSeen but never the current feed's", func(t *testing.T) {
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{first}, {second}, {third, second}}}
+		watcher, _ := New(fake, Options{MaxSeen: 2})
+
+		var delivered collector
+		for i := 0; i < 3; i++ {
+			watcher.Poll(context.Background(), delivered.deliver)
+		}
+
+		seen := watcher.Checkpoint().Seen
+		test.AssertSliceLength(len(seen), 2, t)
+		test.AssertStringMatches(seen[0], Key(third), t)
+		test.AssertStringMatches(seen[1], Key(second), t)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("Resumes from a saved checkpoint", func(t *testing.T) {
+		store := &memoryStore{}
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{second, first}, {third, second, first}}}
+		watcher, _ := New(fake, Options{Store: store})
+		watcher.Poll(context.Background(), (&collector{}).deliver)
+		test.AssertIntMatches(store.saves, 1, t)
+
+		restarted, err := New(fake, Options{Store: store, SkipExisting: true})
+		test.AssertNoError(err, t)
+		var delivered collector
+		restarted.Poll(context.Background(), delivered.deliver)
+		test.AssertSliceLength(len(delivered.payees), 1, t)
+		test.AssertStringMatches(delivered.payees[0], "Third", t)
+	})
+	t.Run("Doesn't save when delivery fails", func(t *testing.T) {
+		store := &memoryStore{}
+		watcher, _ := New(&fakeClient{feeds: [][]models.IndependentExpenditure{{first}}}, Options{Store: store})
+		watcher.Poll(context.Background(), func(ctx context.Context, expenditures []models.IndependentExpenditure) error {
+			return errors.New("database down")
+		})
+		test.AssertIntMatches(store.saves, 0, t)
+	})
+	t.Run("Round-trips a checkpoint through a file", func(t *testing.T) {
+		store := FileStore{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+
+		empty, err := store.Load()
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(empty.Seen), 0, t)
+
+		latest := time.Date(2022, 10, 3, 9, 0, 0, 0, time.UTC)
+		test.AssertNoError(store.Save(Checkpoint{Latest: latest, Seen: []string{"a", "b"}}), t)
+		loaded, err := store.Load()
+		test.AssertNoError(err, t)
+		test.AssertSliceLength(len(loaded.Seen), 2, t)
+		if !loaded.Latest.Equal(latest) {
+			t.Errorf("Wanted %v but got %v", latest, loaded.Latest)
+		}
+	})
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("Sends new transactions on a channel until canceled", func(t *testing.T) {
+		fake := &fakeClient{feeds: [][]models.IndependentExpenditure{{first}, {second, first}}}
+		watcher, _ := New(fake, Options{Interval: time.Millisecond})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		channel := watcher.Watch(ctx)
+		test.AssertStringMatches((<-channel).Payee, "First", t)
+		test.AssertStringMatches((<-channel).Payee, "Second", t)
+		cancel()
+
+		for range channel {
+		}
+	})
+	t.Run("Reports poll errors and keeps polling", func(t *testing.T) {
+		fake := &fakeClient{err: &client.StatusError{StatusCode: 503}}
+		errs := make(chan error, 10)
+		watcher, _ := New(fake, Options{Interval: time.Millisecond, OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- watcher.Run(ctx, (&collector{}).deliver) }()
+		<-errs
+		<-errs
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Wanted context.Canceled but got %v", err)
+		}
+	})
+}